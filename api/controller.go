@@ -1,14 +1,19 @@
-// Generated by swagger/tools/gen-stub --doc openapi.json --src ./api/oapigen --dst ./api
+// Generated by swagger/tools/gen-stub --doc openapi.json --src ./api/oapigen --dst ./api --test
 package api
 
 // ApiController :
 type ApiController struct {
 	*EmojiController
+	*SystemController
 }
 
+// Dependencies :
+type Dependencies struct{}
+
 // NewApiController :
-func NewApiController() *ApiController {
+func NewApiController(deps Dependencies) *ApiController {
 	return &ApiController{
-		EmojiController: NewEmojiController(),
+		EmojiController:  NewEmojiController(),
+		SystemController: NewSystemController(),
 	}
 }
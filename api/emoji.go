@@ -2,11 +2,23 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 
+	"github.com/podhmo/emoji-api/api/encoding"
 	oapigen "github.com/podhmo/emoji-api/api/oapigen"
 	"github.com/podhmo/emoji-api/emojilib"
+	"github.com/podhmo/emoji-api/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/podhmo/emoji-api/api")
+
 type EmojiController struct{}
 
 func NewEmojiController() *EmojiController {
@@ -16,38 +28,193 @@ func NewEmojiController() *EmojiController {
 // Suggest is endpoint of POST /emoji/suggest
 // 先頭一致で対応する文字列を探す
 //
-// * body  :requestBody                         -- "need: var body oapigen.SuggestJSONBody; gctx.ShouldBindJSON(&body); "
+// * body   :requestBody                         -- "request.Body *oapigen.SuggestJSONRequestBody"
+//
+// * 200    :oapigen.Suggest200JSONResponse      -- ""
+// * default:oapigen.SuggestdefaultJSONResponse  -- "default error"
 func (c *EmojiController) Suggest(ctx context.Context, request oapigen.SuggestRequestObject) (response oapigen.SuggestResponseObject, err error) {
 	prefix := request.Body.Prefix
+	if limit := request.Body.Limit; limit != nil && *limit < 0 {
+		response = oapigen.SuggestdefaultJSONResponse{StatusCode: http.StatusBadRequest, Body: oapigen.Error{Message: fmt.Sprintf("limit must not be negative: %d", *limit)}}
+		return
+	}
+	option := NewSuggestOption(*request.Body)
+
+	ctx, span := tracer.Start(ctx, "EmojiController.Suggest", trace.WithAttributes(attribute.Int("emoji.prefix_length", len(prefix))))
+	defer span.End()
+
+	suggestions := emojilib.SuggestContext(ctx, prefix, option)
+	span.SetAttributes(attribute.Int("emoji.result_count", len(suggestions)))
+	logging.FromContext(ctx).DebugContext(ctx, "suggest", "prefix_length", len(prefix), "result_count", len(suggestions))
+	response = oapigen.Suggest200JSONResponse(toEmojiDefinitions(suggestions))
+	return
+}
+
+// Translate is endpoint of POST /emoji/translate
+// :<alias>:のような表現を含んだ文字列をemojiを使った文字列に変換する
+//
+// * body   :requestBody                         -- "request.Body *oapigen.TranslateJSONRequestBody"
+//
+// * 200    :oapigen.Translate200JSONResponse    -- ""
+// * default:oapigen.TranslatedefaultJSONResponse -- "default error"
+func (c *EmojiController) Translate(ctx context.Context, request oapigen.TranslateRequestObject) (response oapigen.TranslateResponseObject, err error) {
+	text := request.Body.Text
+
+	ctx, span := tracer.Start(ctx, "EmojiController.Translate", trace.WithAttributes(attribute.Int("emoji.text_length", len(text))))
+	defer span.End()
+
+	translated := emojilib.TranslateContext(ctx, text)
 
+	response = oapigen.Translate200JSONResponse(translated)
+	return
+}
+
+// NewSuggestOption converts the request body of POST /emoji/suggest to emojilib.SuggestOption (shared with cmd/emoji).
+func NewSuggestOption(body oapigen.SuggestJSONRequestBody) emojilib.SuggestOption {
 	option := emojilib.SuggestOption{}
-	if limit := request.Body.Limit; limit != nil {
+	if limit := body.Limit; limit != nil {
 		option.Limit = *limit
 	}
-	if sort := request.Body.Sort; sort == oapigen.SuggestJSONBodySortDesc {
+	if sort := body.Sort; sort == oapigen.SuggestJSONBodySortDesc {
 		option.Reverse = true
 	}
+	return option
+}
 
-	suggestions := emojilib.Suggest(prefix, option)
-	got := make([]oapigen.EmojiDefinition, len(suggestions))
-	for i, x := range suggestions {
-		got[i] = oapigen.EmojiDefinition{
-			Alias: x.Alias,
-			Char:  x.Char,
+// List is endpoint of GET /emoji
+// the emoji catalog sorted by alias, paged by offset and limit (with the ETag of the catalog)
+//
+// * query  :offset default=0                    -- "the number of the definitions to skip" (request.Params.Offset)
+// * query  :limit default=100                   -- "the number of the definitions in the page" (request.Params.Limit)
+// * header :If-None-Match default=nil           -- "the ETag of the previous response, 304 if not modified" (request.Params.IfNoneMatch)
+//
+// * 200    :oapigen.List200JSONResponse         -- "a page of the emoji catalog"
+// * 304    :oapigen.List304Response             -- "not modified (If-None-Match is matched with the ETag)"
+// * default:oapigen.ListdefaultJSONResponse     -- "default error"
+func (c *EmojiController) List(ctx context.Context, request oapigen.ListRequestObject) (response oapigen.ListResponseObject, err error) {
+	offset, limit := 0, 100
+	if v := request.Params.Offset; v != nil {
+		offset = *v
+	}
+	if v := request.Params.Limit; v != nil {
+		limit = *v
+	}
+	if offset < 0 || limit < 1 || limit > 1000 {
+		response = oapigen.ListdefaultJSONResponse{StatusCode: http.StatusBadRequest, Body: oapigen.Error{Message: fmt.Sprintf("invalid page: offset=%d, limit=%d (offset >= 0, 1 <= limit <= 1000)", offset, limit)}}
+		return
+	}
+
+	etag := catalogETag("")
+	if ifNoneMatch := request.Params.IfNoneMatch; ifNoneMatch != nil && matchETag(*ifNoneMatch, etag) {
+		response = oapigen.List304Response{Headers: oapigen.List304ResponseHeaders{ETag: etag}}
+		return
+	}
+
+	definitions := emojilib.List()
+	page := oapigen.EmojiPage{Items: []oapigen.EmojiDefinition{}, Total: len(definitions)}
+	if offset < len(definitions) {
+		end := offset + limit
+		if end < len(definitions) {
+			page.NextOffset = &end
+		} else {
+			end = len(definitions)
 		}
+		page.Items = toEmojiDefinitions(definitions[offset:end])
 	}
-	response = oapigen.Suggest200JSONResponse(got)
+	response = &catalogResponse{ETag: etag, mediaType: encoding.JSON, encode: func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(page)
+	}}
 	return
 }
 
-// Translate is endpoint of POST /emoji/translate
-// :<alias>:のような表現を含んだ文字列をemojiを使った文字列に変換する
+// Export is endpoint of GET /emoji/export
+// all emoji definitions of the catalog in JSON, NDJSON or CSV (with the ETag of the catalog), for caching the catalog on the client
 //
-// * body  :requestBody                         -- "need: var body oapigen.TranslateJSONBody; gctx.ShouldBindJSON(&body); "
-func (c *EmojiController) Translate(ctx context.Context, request oapigen.TranslateRequestObject) (response oapigen.TranslateResponseObject, err error) {
-	text := request.Body.Text
-	translated := emojilib.Translate(text)
+// * query  :format default=nil                  -- "the representation, selected by the Accept header if omitted (default: json)" (request.Params.Format)
+// * header :If-None-Match default=nil           -- "the ETag of the previous response, 304 if not modified" (request.Params.IfNoneMatch)
+//
+// * 200    :oapigen.Export200JSONResponse       -- ""
+// * 304    :oapigen.Export304Response           -- "not modified (If-None-Match is matched with the ETag)"
+// * default:oapigen.ExportdefaultJSONResponse   -- "default error"
+func (c *EmojiController) Export(ctx context.Context, request oapigen.ExportRequestObject) (response oapigen.ExportResponseObject, err error) {
+	format := oapigen.ExportParamsFormatJson
+	if v := request.Params.Format; v != nil {
+		format = *v
+	}
+	mediaType, ok := encoding.Formats[string(format)]
+	if !ok {
+		response = oapigen.ExportdefaultJSONResponse{StatusCode: http.StatusBadRequest, Body: oapigen.Error{Message: fmt.Sprintf("unsupported format: %q (json, ndjson or csv)", format)}}
+		return
+	}
 
-	response = oapigen.Translate200JSONResponse(translated)
+	etag := catalogETag(string(format))
+	if ifNoneMatch := request.Params.IfNoneMatch; ifNoneMatch != nil && matchETag(*ifNoneMatch, etag) {
+		response = oapigen.Export304Response{Headers: oapigen.Export304ResponseHeaders{ETag: etag}}
+		return
+	}
+
+	definitions := toEmojiDefinitions(emojilib.List())
+	logging.FromContext(ctx).DebugContext(ctx, "export", "format", format, "result_count", len(definitions))
+	response = &catalogResponse{ETag: etag, mediaType: mediaType, encode: func(w io.Writer) error {
+		return encoding.Encode(w, mediaType, definitions)
+	}}
 	return
 }
+
+func toEmojiDefinitions(definitions []emojilib.Definition) []oapigen.EmojiDefinition {
+	r := make([]oapigen.EmojiDefinition, len(definitions))
+	for i, x := range definitions {
+		r[i] = oapigen.EmojiDefinition{
+			Alias: x.Alias,
+			Char:  x.Char,
+		}
+	}
+	return r
+}
+
+// catalogETag returns the ETag of the catalog (weak, the body is compressed by the router), with the format of the export.
+//
+//	W/"<hash>" or W/"<hash>-csv"
+func catalogETag(format string) string {
+	if format == "" {
+		return `W/"` + emojilib.CatalogHash() + `"`
+	}
+	return `W/"` + emojilib.CatalogHash() + "-" + format + `"`
+}
+
+// matchETag reports whether the If-None-Match header matches the etag (the weak comparison).
+func matchETag(ifNoneMatch string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, x := range strings.Split(ifNoneMatch, ",") {
+		x = strings.TrimSpace(x)
+		if x == "*" || strings.TrimPrefix(x, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// catalogResponse is the 200 response of GET /emoji and GET /emoji/export with the ETag of the catalog, written by streaming.
+// The clients revalidate the cached catalog with If-None-Match (Cache-Control: no-cache), 304 is oapigen.List304Response or the like.
+type catalogResponse struct {
+	ETag string
+
+	mediaType string
+	encode    func(w io.Writer) error
+}
+
+func (response *catalogResponse) visit(w http.ResponseWriter) error {
+	w.Header().Set("ETag", response.ETag)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", encoding.ContentType(response.mediaType))
+	w.WriteHeader(http.StatusOK)
+	return response.encode(w)
+}
+
+func (response *catalogResponse) VisitListResponse(w http.ResponseWriter) error {
+	return response.visit(w)
+}
+
+func (response *catalogResponse) VisitExportResponse(w http.ResponseWriter) error {
+	return response.visit(w)
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -14,6 +15,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/podhmo/emoji-api/api"
 	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/emojilib"
 )
 
 // TODO: 404 with application/json
@@ -86,3 +88,153 @@ func TestEmojiSuggest(t *testing.T) {
 		t.Errorf("response body, mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestEmojiSuggestNegativeLimit(t *testing.T) {
+	h := newHandler(newEmojiController())
+
+	req, _ := http.NewRequest("POST", "/emoji/suggest", bytes.NewBufferString(`{"prefix": ":diz", "limit": -1}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := rec.Result()
+
+	if want, got := http.StatusBadRequest, res.StatusCode; want != got {
+		t.Fatalf("status code: want=%d, but got=%d", want, got)
+	}
+
+	var got oapigen.Error
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Errorf("unexpected error (json.Unmarshal): %+v", err)
+	}
+	defer res.Body.Close()
+
+	want := oapigen.Error{Message: "limit must not be negative: -1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("response body, mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEmojiList(t *testing.T) {
+	h := newHandler(newEmojiController())
+	total := len(emojilib.List())
+	etag := `W/"` + emojilib.CatalogHash() + `"`
+
+	tests := []struct {
+		name        string
+		path        string
+		ifNoneMatch string
+		wantStatus  int
+		wantItems   int
+		wantNext    *int
+	}{
+		{name: "default", path: "/emoji", wantStatus: http.StatusOK, wantItems: 100, wantNext: ptr(100)},
+		{name: "paged", path: "/emoji?offset=10&limit=5", wantStatus: http.StatusOK, wantItems: 5, wantNext: ptr(15)},
+		{name: "last-page", path: "/emoji?offset=" + strconv.Itoa(total-3) + "&limit=5", wantStatus: http.StatusOK, wantItems: 3},
+		{name: "out-of-range", path: "/emoji?offset=" + strconv.Itoa(total+1), wantStatus: http.StatusOK, wantItems: 0},
+		{name: "negative-offset", path: "/emoji?offset=-1", wantStatus: http.StatusBadRequest},
+		{name: "too-large-limit", path: "/emoji?limit=1001", wantStatus: http.StatusBadRequest},
+		{name: "not-modified", path: "/emoji", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "not-modified-strong", path: "/emoji", ifNoneMatch: `"xxx", "` + emojilib.CatalogHash() + `"`, wantStatus: http.StatusNotModified},
+		{name: "modified", path: "/emoji?limit=1", ifNoneMatch: `W/"xxx"`, wantStatus: http.StatusOK, wantItems: 1, wantNext: ptr(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				return
+			}
+			if want, got := etag, res.Header.Get("ETag"); want != got {
+				t.Errorf("ETag: want=%q, but got=%q", want, got)
+			}
+			if tt.wantStatus == http.StatusNotModified {
+				if rec.Body.Len() != 0 {
+					t.Errorf("unexpected body: %q", rec.Body.String())
+				}
+				return
+			}
+
+			var got oapigen.EmojiPage
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Errorf("unexpected error (json.Unmarshal): %+v", err)
+			}
+			defer res.Body.Close()
+
+			if want, got := tt.wantItems, len(got.Items); want != got {
+				t.Errorf("len(items): want=%d, but got=%d", want, got)
+			}
+			if want, got := total, got.Total; want != got {
+				t.Errorf("total: want=%d, but got=%d", want, got)
+			}
+			if diff := cmp.Diff(tt.wantNext, got.NextOffset); diff != "" {
+				t.Errorf("nextOffset, mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEmojiExport(t *testing.T) {
+	h := newHandler(newEmojiController())
+	total := len(emojilib.List())
+
+	tests := []struct {
+		name            string
+		path            string
+		ifNoneMatch     string
+		wantStatus      int
+		wantContentType string
+		wantETag        string
+		wantLines       int
+	}{
+		{name: "default", path: "/emoji/export", wantStatus: http.StatusOK, wantContentType: "application/json", wantETag: "json", wantLines: 1},
+		{name: "json", path: "/emoji/export?format=json", wantStatus: http.StatusOK, wantContentType: "application/json", wantETag: "json", wantLines: 1},
+		{name: "ndjson", path: "/emoji/export?format=ndjson", wantStatus: http.StatusOK, wantContentType: "application/x-ndjson", wantETag: "ndjson", wantLines: total},
+		{name: "csv", path: "/emoji/export?format=csv", wantStatus: http.StatusOK, wantContentType: "text/csv; charset=utf-8", wantETag: "csv", wantLines: total + 1},
+		{name: "not-modified", path: "/emoji/export?format=csv", ifNoneMatch: `W/"` + emojilib.CatalogHash() + `-csv"`, wantStatus: http.StatusNotModified, wantETag: "csv"},
+		{name: "another-format", path: "/emoji/export?format=ndjson", ifNoneMatch: `W/"` + emojilib.CatalogHash() + `-csv"`, wantStatus: http.StatusOK, wantContentType: "application/x-ndjson", wantETag: "ndjson", wantLines: total},
+		{name: "unsupported", path: "/emoji/export?format=xml", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				return
+			}
+			if want, got := `W/"`+emojilib.CatalogHash()+"-"+tt.wantETag+`"`, res.Header.Get("ETag"); want != got {
+				t.Errorf("ETag: want=%q, but got=%q", want, got)
+			}
+			if want, got := tt.wantContentType, res.Header.Get("Content-Type"); tt.wantStatus == http.StatusOK && want != got {
+				t.Errorf("Content-Type: want=%q, but got=%q", want, got)
+			}
+			if want, got := tt.wantLines, strings.Count(rec.Body.String(), "\n"); want != got {
+				t.Errorf("the number of the lines: want=%d, but got=%d", want, got)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
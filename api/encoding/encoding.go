@@ -0,0 +1,170 @@
+// Package encoding is the alternative representations of []EmojiDefinition (JSON, NDJSON and CSV), selected by the Accept header.
+package encoding
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+)
+
+// the media types of []EmojiDefinition
+const (
+	JSON   = "application/json"
+	NDJSON = "application/x-ndjson"
+	CSV    = "text/csv"
+)
+
+// MediaTypes is the supported media types, in the order of preference (JSON is the default).
+var MediaTypes = []string{JSON, NDJSON, CSV}
+
+// Formats is the media types by the format name (e.g. GET /emoji/export?format=csv).
+var Formats = map[string]string{"json": JSON, "ndjson": NDJSON, "csv": CSV}
+
+// Format returns the format name of the media type, or "" if it is not supported.
+func Format(mediaType string) string {
+	for name, mt := range Formats {
+		if mt == mediaType {
+			return name
+		}
+	}
+	return ""
+}
+
+// ContentType returns the value of the Content-Type header of the media type.
+func ContentType(mediaType string) string {
+	if mediaType == CSV {
+		return CSV + "; charset=utf-8"
+	}
+	return mediaType
+}
+
+// Negotiate returns the media type for the Accept header (with the quality values), or "" if nothing is acceptable.
+// JSON is returned if the header is empty.
+//
+//	"text/csv" -> text/csv
+//	"application/x-ndjson;q=0.5, application/json" -> application/json
+//	"*/*" -> application/json
+func Negotiate(accept string) string {
+	return Select(accept, MediaTypes)
+}
+
+// Select returns the media type in mediaTypes (in the order of preference) for the Accept header, or "" if nothing is acceptable.
+// The first one is returned if the header is empty.
+func Select(accept string, mediaTypes []string) string {
+	if len(mediaTypes) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(accept) == "" {
+		return mediaTypes[0]
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+		order     int // the preference of the server
+	}
+	var candidates []candidate
+	for i, mediaType := range mediaTypes {
+		if q := quality(accept, mediaType); q > 0 {
+			candidates = append(candidates, candidate{mediaType: mediaType, q: q, order: i})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].mediaType
+}
+
+// quality returns the q value of the most specific media range in accept matching the media type (0 if not matched).
+func quality(accept string, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		var s int
+		switch {
+		case mediaRange == mediaType:
+			s = 2
+		case mediaRange == typ+"/*":
+			s = 1
+		case mediaRange == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity = s
+		q = 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+	}
+	return q
+}
+
+// Encode writes the definitions in the media type, one by one (the JSON array is not buffered).
+//
+//   - application/json: [{"alias": ":dizzy:", "char": "💫"}, ...]
+//   - application/x-ndjson: {"alias": ":dizzy:", "char": "💫"} for each line
+//   - text/csv: the header (alias,char) and the rows
+func Encode(w io.Writer, mediaType string, definitions []oapigen.EmojiDefinition) error {
+	switch mediaType {
+	case JSON:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+		for i, d := range definitions {
+			if i > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			b, err := json.Marshal(d)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, "]\n")
+		return err
+	case NDJSON:
+		enc := json.NewEncoder(w)
+		for _, d := range definitions {
+			if err := enc.Encode(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"alias", "char"}); err != nil {
+			return err
+		}
+		for _, d := range definitions {
+			if err := cw.Write([]string{d.Alias, d.Char}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported media type: %q", mediaType)
+	}
+}
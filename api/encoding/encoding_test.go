@@ -0,0 +1,88 @@
+package encoding_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/api/encoding"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: encoding.JSON},
+		{accept: "*/*", want: encoding.JSON},
+		{accept: "application/json", want: encoding.JSON},
+		{accept: "application/x-ndjson", want: encoding.NDJSON},
+		{accept: "text/csv", want: encoding.CSV},
+		{accept: "text/*", want: encoding.CSV},
+		{accept: "text/html, text/csv;q=0.9, */*;q=0.1", want: encoding.CSV},
+		{accept: "application/x-ndjson;q=0.5, application/json", want: encoding.JSON},
+		{accept: "application/*;q=0.5, application/x-ndjson", want: encoding.NDJSON},
+		{accept: "*/*, application/json;q=0", want: encoding.NDJSON}, // json is excluded explicitly
+		{accept: "text/html", want: ""},
+		{accept: "invalid", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			if got := encoding.Negotiate(tt.accept); got != tt.want {
+				t.Errorf("Negotiate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name       string
+		accept     string
+		mediaTypes []string
+		want       string
+	}{
+		{name: "empty-accept", accept: "", mediaTypes: []string{"text/plain", encoding.JSON}, want: "text/plain"},
+		{name: "matched", accept: "text/*", mediaTypes: []string{encoding.JSON, "text/plain"}, want: "text/plain"},
+		{name: "not-acceptable", accept: "text/csv", mediaTypes: []string{encoding.JSON}, want: ""},
+		{name: "no-media-types", accept: "*/*", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encoding.Select(tt.accept, tt.mediaTypes); got != tt.want {
+				t.Errorf("Select() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	definitions := []oapigen.EmojiDefinition{{Alias: ":dizzy:", Char: "💫"}, {Alias: ":dizzy_face:", Char: "😵"}}
+
+	tests := []struct {
+		mediaType string
+		want      string
+	}{
+		{mediaType: encoding.JSON, want: `[{"alias":":dizzy:","char":"💫"},{"alias":":dizzy_face:","char":"😵"}]` + "\n"},
+		{mediaType: encoding.NDJSON, want: `{"alias":":dizzy:","char":"💫"}` + "\n" + `{"alias":":dizzy_face:","char":"😵"}` + "\n"},
+		{mediaType: encoding.CSV, want: "alias,char\n:dizzy:,💫\n:dizzy_face:,😵\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mediaType, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := encoding.Encode(buf, tt.mediaType, definitions); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Encode() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if err := encoding.Encode(new(bytes.Buffer), "text/html", definitions); err == nil {
+			t.Errorf("want error, but nil")
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/logging"
+)
+
+// Auth authenticates the API key (Authorization: Bearer <key> or X-API-Key) of the operations having the security requirements
+// (marked in the context by oapigen, e.g. oapigen.BearerAuthScopes), before decoding the request.
+// The scopes are x-required-scopes of the operation in openapi.json (OAS 3.0 doesn't allow the scopes of http/apiKey schemes).
+//
+//   - no key or unknown key -> 401
+//   - the key without the scopes -> 403
+func Auth(store auth.KeyStore) oapigen.MiddlewareFunc {
+	operationScopes, err := OperationScopes()
+	if err != nil {
+		panic(fmt.Sprintf("load the scopes from the embedded openapi doc: %+v", err)) // the doc is generated, never broken
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if !secured(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			scopes := operationScopes[r.Method+" "+chi.RouteContext(ctx).RoutePattern()]
+
+			challenge := `Bearer realm="emoji-api"`
+			key := APIKey(r)
+			if key == "" {
+				WriteError(w, r, &HTTPError{StatusCode: http.StatusUnauthorized, Message: "API key is required", Header: http.Header{"WWW-Authenticate": []string{challenge}}})
+				return
+			}
+			k, err := store.Lookup(ctx, key)
+			if err != nil {
+				if !errors.Is(err, auth.ErrUnknownKey) {
+					logging.FromContext(ctx).ErrorContext(ctx, "key store failed", "error", err)
+				}
+				WriteError(w, r, &HTTPError{StatusCode: http.StatusUnauthorized, Message: "invalid API key", Header: http.Header{"WWW-Authenticate": []string{challenge + `, error="invalid_token"`}}})
+				return
+			}
+			if !k.HasScopes(scopes...) {
+				challenge += `, error="insufficient_scope", scope="` + strings.Join(scopes, " ") + `"`
+				WriteError(w, r, &HTTPError{StatusCode: http.StatusForbidden, Message: "insufficient scope, required: " + strings.Join(scopes, ", "), Header: http.Header{"WWW-Authenticate": []string{challenge}}})
+				return
+			}
+
+			ctx = auth.WithKey(ctx, k)
+			ctx = logging.With(ctx, "api_key", k.Name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// secured reports whether the operation has the security requirements (the scopes are set in the context by oapigen).
+func secured(r *http.Request) bool {
+	for _, k := range []string{oapigen.BearerAuthScopes, oapigen.ApiKeyAuthScopes} {
+		if _, ok := r.Context().Value(k).([]string); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiredScopesExtension is the extension of the operation, having the scopes of the API key (seed/tools/gen-doc).
+const RequiredScopesExtension = "x-required-scopes"
+
+// OperationScopes returns the scopes required by the operations, keyed by "<METHOD> <path>" (e.g. "POST /emoji/suggest").
+func OperationScopes() (map[string][]string, error) {
+	doc, err := oapigen.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	r := map[string][]string{}
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			v, ok := op.Extensions[RequiredScopesExtension]
+			if !ok {
+				continue
+			}
+			b, err := json.Marshal(v) // json.RawMessage or []any
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			var scopes []string
+			if err := json.Unmarshal(b, &scopes); err != nil {
+				return nil, fmt.Errorf("%s %s: %s: %w", method, path, RequiredScopesExtension, err)
+			}
+			r[strings.ToUpper(method)+" "+path] = scopes
+		}
+	}
+	return r, nil
+}
+
+// APIKey returns the API key of the request, from X-API-Key or Authorization: Bearer <key>.
+func APIKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
@@ -0,0 +1,24 @@
+package middleware_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/api/middleware"
+)
+
+func TestOperationScopes(t *testing.T) {
+	got, err := middleware.OperationScopes()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	want := map[string][]string{
+		"GET /emoji":            {"read"},
+		"GET /emoji/export":     {"read"},
+		"POST /emoji/suggest":   {"read"},
+		"POST /emoji/translate": {"read"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("OperationScopes() mismatch (-want +got):\n%s", diff)
+	}
+}
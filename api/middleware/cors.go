@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CORSConfig is the configuration of CORS, it is disabled if AllowedOrigins is empty.
+type CORSConfig struct {
+	AllowedOrigins []string      // e.g. https://example.com, https://*.example.com, or * (any origin)
+	AllowedHeaders []string      // the request headers, default: DefaultCORSAllowedHeaders
+	ExposedHeaders []string      // the response headers readable by the browser, default: DefaultCORSExposedHeaders
+	MaxAge         time.Duration // the cache duration of the preflight response, default: 10 minutes
+}
+
+var (
+	DefaultCORSAllowedHeaders = []string{"Content-Type", "Authorization", APIKeyHeader, RequestIDHeader, "Traceparent", "If-None-Match"}
+	DefaultCORSExposedHeaders = []string{RequestIDHeader, "Retry-After", "ETag"}
+)
+
+// the methods answered in Access-Control-Allow-Methods, if routed
+var corsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// CORS adds the CORS headers for the allowed origins, and answers the preflight requests (OPTIONS) for the routes of routes
+// (the router of the operations in openapi.json). The preflight of the other paths is passed to the next handler (e.g. 405).
+func CORS(config CORSConfig, routes chi.Routes) func(http.Handler) http.Handler {
+	if config.AllowedHeaders == nil {
+		config.AllowedHeaders = DefaultCORSAllowedHeaders
+	}
+	if config.ExposedHeaders == nil {
+		config.ExposedHeaders = DefaultCORSExposedHeaders
+	}
+	if config.MaxAge == 0 {
+		config.MaxAge = 10 * time.Minute
+	}
+	allowedHeaders := make(map[string]bool, len(config.AllowedHeaders))
+	for _, h := range config.AllowedHeaders {
+		allowedHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+	exposed := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		if len(config.AllowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			requestMethod := r.Header.Get("Access-Control-Request-Method")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// actual request
+			if r.Method != http.MethodOptions || requestMethod == "" {
+				w.Header().Add("Vary", "Origin")
+				if allowedOrigin(config.AllowedOrigins, origin) {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					if exposed != "" {
+						w.Header().Set("Access-Control-Expose-Headers", exposed)
+					}
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// preflight request
+			if !routes.Match(chi.NewRouteContext(), requestMethod, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			if !allowedOrigin(config.AllowedOrigins, origin) {
+				w.WriteHeader(http.StatusNoContent) // without the CORS headers, the browser rejects the request
+				return
+			}
+			var headers []string
+			for _, x := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+				if x = strings.TrimSpace(x); x == "" {
+					continue
+				}
+				if !allowedHeaders[http.CanonicalHeaderKey(x)] {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				headers = append(headers, x)
+			}
+
+			var methods []string
+			for _, m := range corsMethods {
+				if routes.Match(chi.NewRouteContext(), m, r.URL.Path) {
+					methods = append(methods, m)
+				}
+			}
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if len(headers) > 0 {
+				h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+			}
+			h.Set("Access-Control-Max-Age", maxAge)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// allowedOrigin reports whether the origin is matched with one of the patterns (*, or the wildcard of the subdomain).
+func allowedOrigin(patterns []string, origin string) bool {
+	for _, p := range patterns {
+		if p == "*" || strings.EqualFold(p, origin) {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(p, "*"); ok {
+			// https://*.example.com matches https://a.example.com (not https://example.com)
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+)
+
+// HTTPError is the error returned from the strict middlewares to respond with the status code other than 500 (e.g. 429).
+// It is written as the Error JSON by WriteError().
+type HTTPError struct {
+	StatusCode int
+	Message    string
+	Header     http.Header // the extra headers (e.g. Retry-After)
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// WriteError writes err as the Error JSON, the status code is 500 unless err is *HTTPError
+// (for oapigen.StrictHTTPServerOptions.ResponseErrorHandlerFunc).
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	code := http.StatusInternalServerError
+	body := oapigen.Error{Message: err.Error()}
+
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		code = herr.StatusCode
+		body.Message = herr.Message
+		for k, vs := range herr.Header {
+			for _, v := range vs {
+				w.Header().Add(k, v)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body) // nolint
+}
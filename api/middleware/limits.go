@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/emojilib"
+)
+
+// the default limits, documented in openapi.json (seed/design/action/actions.go)
+const (
+	DefaultMaxBodyBytes   = 256 << 10
+	DefaultMaxTextLength  = 10000 // the maxLength of the text of translate
+	DefaultMaxAliasTokens = 1000
+)
+
+// LimitsConfig is the limits of the requests. The zero value means the default, and the negative value means unlimited.
+type LimitsConfig struct {
+	MaxBodyBytes   int64 // the size of the request body (413)
+	MaxTextLength  int   // the number of the characters of the text of translate (422)
+	MaxAliasTokens int   // the number of the :<alias>: tokens in the text of translate (422)
+}
+
+// WithDefaults returns the config, the zero values are replaced with the defaults (shared with the gRPC interceptor).
+func (c LimitsConfig) WithDefaults() LimitsConfig {
+	if c.MaxBodyBytes == 0 {
+		c.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if c.MaxTextLength == 0 {
+		c.MaxTextLength = DefaultMaxTextLength
+	}
+	if c.MaxAliasTokens == 0 {
+		c.MaxAliasTokens = DefaultMaxAliasTokens
+	}
+	return c
+}
+
+// MaxBytes limits the size of the request body, the decoding in the strict handler fails with *http.MaxBytesError
+// (written as 413 by WriteRequestError()).
+func MaxBytes(config LimitsConfig) oapigen.MiddlewareFunc {
+	n := config.WithDefaults().MaxBodyBytes
+	return func(next http.Handler) http.Handler {
+		if n < 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				WriteRequestError(w, r, &http.MaxBytesError{Limit: n})
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Limits rejects the decoded request exceeding the limits with 422, before calling the controller.
+//
+//   - Translate: the length of the text, and the number of the :<alias>: tokens
+func Limits(config LimitsConfig) oapigen.StrictMiddlewareFunc {
+	config = config.WithDefaults()
+	return func(f oapigen.StrictHandlerFunc, operationID string) oapigen.StrictHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			switch request := request.(type) {
+			case oapigen.TranslateRequestObject:
+				if request.Body == nil {
+					break
+				}
+				text := request.Body.Text
+				if n, max := utf8.RuneCountInString(text), config.MaxTextLength; max >= 0 && n > max {
+					return nil, &HTTPError{StatusCode: http.StatusUnprocessableEntity, Message: fmt.Sprintf("text is too long: %d characters (max %d)", n, max)}
+				}
+				if n, max := emojilib.CountAliases(text), config.MaxAliasTokens; max >= 0 && n > max {
+					return nil, &HTTPError{StatusCode: http.StatusUnprocessableEntity, Message: fmt.Sprintf("too many :<alias>: tokens: %d (max %d)", n, max)}
+				}
+			}
+			return f(ctx, w, r, request)
+		}
+	}
+}
+
+// WriteRequestError writes the error of decoding the request as the Error JSON, 413 if the body is too large, otherwise 400
+// (for oapigen.StrictHTTPServerOptions.RequestErrorHandlerFunc).
+func WriteRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		WriteError(w, r, &HTTPError{StatusCode: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("request body is too large (max %d bytes)", maxBytesErr.Limit)})
+		return
+	}
+	WriteError(w, r, &HTTPError{StatusCode: http.StatusBadRequest, Message: err.Error()})
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"regexp"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header of the request ID, the incoming value is used if it is valid.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID set by Logging().
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+var rxRequestID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,128}$`)
+
+// requestInfo is the information filled by the inner middlewares (e.g. the operation name by LogOperation()).
+type requestInfo struct {
+	operation string
+}
+
+type requestInfoKey struct{}
+
+// Logging writes the access log for each request, and carries the logger having request_id in the context.
+// The request ID is taken from X-Request-ID (if valid) or generated, and returned as X-Request-ID.
+// trace_id is added too, if the request span is started by the outer middleware (TraceHTTP()).
+//
+//	level=INFO msg=request request_id=... trace_id=... method=POST path=/emoji/suggest operation=Suggest status=200 latency=...
+func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			id := r.Header.Get(RequestIDHeader)
+			if !rxRequestID.MatchString(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			l := logger.With("request_id", id)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				l = l.With("trace_id", sc.TraceID().String())
+			}
+			info := &requestInfo{}
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			ctx = context.WithValue(ctx, requestInfoKey{}, info)
+			ctx = logging.WithLogger(ctx, l)
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= 500 {
+				level = slog.LevelError
+			}
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int("bytes", ww.BytesWritten()),
+			}
+			if info.operation != "" {
+				attrs = append(attrs, slog.String("operation", info.operation))
+			}
+			l.LogAttrs(r.Context(), level, "request", attrs...)
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// LogOperation adds the operation name to the logger in the context (and the access log of Logging()),
+// and writes the sanitized summary of the request at debug level (the strings are logged as their length only).
+func LogOperation() oapigen.StrictMiddlewareFunc {
+	return func(f oapigen.StrictHandlerFunc, operationID string) oapigen.StrictHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+				info.operation = operationID
+			}
+			ctx = logging.With(ctx, "operation", operationID)
+
+			logger := logging.FromContext(ctx)
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				logger.DebugContext(ctx, "operation", "request", Summarize(request))
+			}
+
+			response, err := f(ctx, w, r, request)
+			if err != nil {
+				level := slog.LevelError
+				if StatusCode(err) < 500 { // e.g. 429 by RateLimit()
+					level = slog.LevelWarn
+				}
+				logger.Log(ctx, level, "operation failed", "error", err)
+			}
+			return response, err
+		}
+	}
+}
+
+// Summarize returns the summary of the request object, without the raw string values (e.g. the text of translate).
+//
+//	oapigen.SuggestRequestObject{Body: &{Prefix: ":diz", Sort: "asc"}} -> {"Body": {"Limit": nil, "Prefix": "len=4", "Sort": "asc"}}
+func Summarize(v interface{}) slog.Value {
+	return summarize(reflect.ValueOf(v), 0)
+}
+
+func summarize(rv reflect.Value, depth int) slog.Value {
+	if depth > 4 {
+		return slog.StringValue("...")
+	}
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		if rv.Type().PkgPath() != "" { // named string types are the enums (e.g. SuggestJSONBodySort), safe to log
+			return slog.StringValue(rv.String())
+		}
+		return slog.StringValue(fmt.Sprintf("len=%d", rv.Len()))
+	case reflect.Bool:
+		return slog.BoolValue(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return slog.Int64Value(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return slog.Uint64Value(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return slog.Float64Value(rv.Float())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return slog.StringValue(fmt.Sprintf("len=%d", rv.Len()))
+	case reflect.Struct:
+		attrs := make([]slog.Attr, 0, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			if f := rv.Type().Field(i); f.IsExported() {
+				attrs = append(attrs, slog.Attr{Key: f.Name, Value: summarize(rv.Field(i), depth+1)})
+			}
+		}
+		return slog.GroupValue(attrs...)
+	default:
+		return slog.StringValue(rv.Type().String())
+	}
+}
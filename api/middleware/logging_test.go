@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/api/middleware"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+)
+
+func TestSummarize(t *testing.T) {
+	limit := 10
+
+	cases := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{
+			name:  "translate",
+			input: oapigen.TranslateRequestObject{Body: &oapigen.TranslateJSONRequestBody{Text: "secret :dizzy:"}},
+			want:  "[Body=[Text=len=14]]",
+		},
+		{
+			name:  "suggest",
+			input: oapigen.SuggestRequestObject{Body: &oapigen.SuggestJSONRequestBody{Prefix: ":diz", Limit: &limit, Sort: oapigen.SuggestJSONBodySortAsc}},
+			want:  "[Body=[Limit=10 Prefix=len=4 Sort=asc]]",
+		},
+		{
+			name:  "nil body",
+			input: oapigen.SuggestRequestObject{},
+			want:  "[Body=<nil>]",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			got := middleware.Summarize(c.input).String()
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Errorf("Summarize() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
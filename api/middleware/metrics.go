@@ -0,0 +1,104 @@
+// Package middleware is the set of oapigen.StrictMiddlewareFunc (the operation name is passed, e.g. "Suggest").
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+type operationMetrics struct {
+	requests *metrics.CounterVec
+	errors   *metrics.CounterVec
+	latency  *metrics.HistogramVec
+}
+
+// Metrics counts the requests, the errors and the latency for each operation, with the status code written to the client.
+// This is the outermost middleware for the operations of oapigen (ChiServerOptions.Middlewares), then the responses of
+// the other middlewares (e.g. 401 by Auth(), 413 by MaxBytes()) and the decoding errors (400) are counted, too.
+//
+//	emoji_api_requests_total{operation="Suggest",code="200"} 1
+//	emoji_api_errors_total{operation="Suggest"} 0
+//	emoji_api_request_duration_seconds_bucket{operation="Suggest",le="0.005"} 1
+func Metrics(registry *metrics.Registry) oapigen.MiddlewareFunc {
+	m := &operationMetrics{
+		requests: registry.NewCounterVec("emoji_api_requests_total", "the number of requests for each operation", "operation", "code"),
+		errors:   registry.NewCounterVec("emoji_api_errors_total", "the number of error responses (status >= 400) for each operation", "operation"),
+		latency:  registry.NewHistogramVec("emoji_api_request_duration_seconds", "the latency of the operations", metrics.DefBuckets, "operation"),
+	}
+	operationNames, err := OperationNames()
+	if err != nil {
+		panic(fmt.Sprintf("load the operations from the embedded openapi doc: %+v", err)) // the doc is generated, never broken
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operation := operationNames[r.Method+" "+chi.RouteContext(r.Context()).RoutePattern()]
+
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+			m.latency.With(operation).Observe(time.Since(start).Seconds())
+
+			code := ww.Status()
+			if code == 0 {
+				code = http.StatusOK
+			}
+			m.requests.With(operation, strconv.Itoa(code)).Inc()
+			if code >= 400 {
+				m.errors.With(operation).Inc()
+			}
+		})
+	}
+}
+
+// OperationNames returns the names of the operations (the methods of oapigen.StrictServerInterface, e.g. "Suggest"),
+// keyed by "<METHOD> <path>" (e.g. "POST /emoji/suggest").
+func OperationNames() (map[string]string, error) {
+	doc, err := oapigen.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	r := map[string]string{}
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			r[strings.ToUpper(method)+" "+path] = operationName(op.OperationID)
+		}
+	}
+	return r, nil
+}
+
+// operationName converts the operationId to the method name, as oapi-codegen does (e.g. get-emoji -> GetEmoji).
+func operationName(operationID string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range operationID {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+			continue
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// StatusCode returns the status code of the error returned by the strict handler (500, or the StatusCode of *HTTPError).
+func StatusCode(err error) int {
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		return herr.StatusCode
+	}
+	return http.StatusInternalServerError
+}
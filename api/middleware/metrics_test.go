@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api/middleware"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "error", err: errors.New("unexpected"), want: 500},
+		{name: "http-error", err: &middleware.HTTPError{StatusCode: 429}, want: 429},
+		{name: "wrapped-http-error", err: fmt.Errorf("wrapped: %w", &middleware.HTTPError{StatusCode: 413}), want: 413},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := middleware.StatusCode(tt.err); got != tt.want {
+				t.Errorf("StatusCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperationNames(t *testing.T) {
+	got, err := middleware.OperationNames()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if want, got := "Suggest", got["POST /emoji/suggest"]; want != got {
+		t.Errorf("the name of POST /emoji/suggest: want=%q, but got=%q", want, got)
+	}
+
+	// the names are the methods of the strict server interface
+	ssi := reflect.TypeOf((*oapigen.StrictServerInterface)(nil)).Elem()
+	for k, name := range got {
+		if _, ok := ssi.MethodByName(name); !ok {
+			t.Errorf("the name of %s is not the method of StrictServerInterface: %q", k, name)
+		}
+	}
+	if want, got := ssi.NumMethod(), len(got); want != got {
+		t.Errorf("the number of the operations: want=%d, but got=%d", want, got)
+	}
+}
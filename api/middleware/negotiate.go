@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/podhmo/emoji-api/api/encoding"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+)
+
+// Negotiate encodes the []EmojiDefinition responses in the media type selected by the Accept header
+// (JSON, NDJSON or CSV, see the encoding package). JSON is used if nothing is acceptable.
+// The operations declare the media types in openapi.json (Produces() in seed/design/action).
+//
+//   - Suggest: the 200 response is encoded in the selected media type
+//   - Export: the format parameter is set by the Accept header, if omitted
+func Negotiate() oapigen.StrictMiddlewareFunc {
+	return func(f oapigen.StrictHandlerFunc, operationID string) oapigen.StrictHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			if req, ok := request.(oapigen.ExportRequestObject); ok && req.Params.Format == nil {
+				w.Header().Add("Vary", "Accept")
+				if format := encoding.Format(encoding.Negotiate(r.Header.Get("Accept"))); format != "" {
+					v := oapigen.ExportParamsFormat(format)
+					req.Params.Format = &v
+					request = req
+				}
+			}
+
+			response, err := f(ctx, w, r, request)
+			if err != nil {
+				return response, err
+			}
+
+			switch response := response.(type) {
+			case oapigen.Suggest200JSONResponse:
+				w.Header().Add("Vary", "Accept")
+				if mediaType := encoding.Negotiate(r.Header.Get("Accept")); mediaType != "" && mediaType != encoding.JSON {
+					return &definitionsResponse{mediaType: mediaType, definitions: response}, nil
+				}
+			}
+			return response, err
+		}
+	}
+}
+
+// definitionsResponse is the 200 response of []EmojiDefinition in the media type other than JSON.
+type definitionsResponse struct {
+	mediaType   string
+	definitions []oapigen.EmojiDefinition
+}
+
+func (response *definitionsResponse) visit(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", encoding.ContentType(response.mediaType))
+	w.WriteHeader(http.StatusOK)
+	return encoding.Encode(w, response.mediaType, response.definitions)
+}
+
+func (response *definitionsResponse) VisitSuggestResponse(w http.ResponseWriter) error {
+	return response.visit(w)
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/logging"
+	"github.com/podhmo/emoji-api/ratelimit"
+)
+
+// APIKeyHeader is the header of the API key (design.APIKeyAuth), also used as the key of the rate limit.
+const APIKeyHeader = "X-API-Key"
+
+type RateLimitConfig struct {
+	Limits map[string]ratelimit.Limit   // the limit for each operation (e.g. "Suggest"), the operations not found are not limited
+	Store  ratelimit.Store              // default: ratelimit.NewMemoryStore()
+	Key    func(r *http.Request) string // default: ClientKey (the authenticated API key or the remote IP)
+	Now    func() time.Time             // default: time.Now
+}
+
+// RateLimit limits the requests for each operation and client with the token bucket.
+// If the bucket is empty, 429 is returned as the Error JSON with Retry-After (via *HTTPError).
+// The requests are allowed if the store fails (fail open).
+func RateLimit(config RateLimitConfig) oapigen.StrictMiddlewareFunc {
+	if config.Store == nil {
+		config.Store = ratelimit.NewMemoryStore()
+	}
+	if config.Key == nil {
+		config.Key = ClientKey
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return func(f oapigen.StrictHandlerFunc, operationID string) oapigen.StrictHandlerFunc {
+		limit, ok := config.Limits[operationID]
+		if !ok {
+			return f
+		}
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			result, err := config.Store.Take(ctx, operationID+":"+config.Key(r), limit, config.Now())
+			if err != nil {
+				logging.FromContext(ctx).WarnContext(ctx, "rate limit store failed", "operation", operationID, "error", err)
+				return f(ctx, w, r, request)
+			}
+			if !result.Allowed {
+				retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+				return nil, &HTTPError{
+					StatusCode: http.StatusTooManyRequests,
+					Message:    "rate limit exceeded, retry after " + strconv.Itoa(retryAfter) + "s",
+					Header:     http.Header{"Retry-After": []string{strconv.Itoa(retryAfter)}},
+				}
+			}
+			return f(ctx, w, r, request)
+		}
+	}
+}
+
+// ClientKey returns the key of the client, the authenticated API key (auth.KeyFromContext(), set by Auth()) or the remote IP.
+// The API key not authenticated (e.g. the auth is disabled) is ignored, otherwise a client could escape the limit with junk keys.
+//
+//	"key:<sha256 prefix>" or "ip:127.0.0.1"
+func ClientKey(r *http.Request) string {
+	if k, ok := auth.KeyFromContext(r.Context()); ok && len(k.SHA256) >= 16 {
+		return "key:" + k.SHA256[:16]
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,42 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api/middleware"
+	"github.com/podhmo/emoji-api/auth"
+)
+
+func TestClientKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     map[string]string
+		key        *auth.Key // authenticated
+		want       string
+	}{
+		{name: "ip", remoteAddr: "192.0.2.1:1234", want: "ip:192.0.2.1"},
+		{name: "ipv6", remoteAddr: "[2001:db8::1]:1234", want: "ip:2001:db8::1"},
+		{name: "no-port", remoteAddr: "192.0.2.1", want: "ip:192.0.2.1"},
+		{name: "authenticated", remoteAddr: "192.0.2.1:1234", header: map[string]string{"X-API-Key": "xxx"},
+			key: &auth.Key{Name: "local", SHA256: "cd2eb0837c9b4c962c22d2ff8b5441b7b45805887f051d39bf133b583baf6860"}, want: "key:cd2eb0837c9b4c96"},
+		{name: "api-key-not-authenticated", remoteAddr: "192.0.2.1:1234", header: map[string]string{"X-API-Key": "xxx"}, want: "ip:192.0.2.1"},
+		{name: "bearer-not-authenticated", remoteAddr: "192.0.2.1:1234", header: map[string]string{"Authorization": "Bearer xxx"}, want: "ip:192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			if tt.key != nil {
+				req = req.WithContext(auth.WithKey(req.Context(), tt.key))
+			}
+			if got := middleware.ClientKey(req); got != tt.want {
+				t.Errorf("ClientKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
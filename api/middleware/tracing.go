@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the spans started by this package.
+const TracerName = "github.com/podhmo/emoji-api/api/middleware"
+
+type decodeSpanKey struct{}
+
+// TraceHTTP starts the span for each request (the parent is extracted from the W3C traceparent header).
+// With TraceDecode() and Tracing(), the spans are nested as below.
+//
+//	POST /emoji/suggest
+//	├── strictHandler.decode
+//	└── EmojiController.Suggest
+//	    └── emojilib.Suggest
+func TraceHTTP(tp trace.TracerProvider) func(http.Handler) http.Handler {
+	tracer := tp.Tracer(TracerName)
+	propagator := propagation.TraceContext{}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.method", r.Method),
+					attribute.String("http.target", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(attribute.Int("http.status_code", status))
+			if status >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		})
+	}
+}
+
+// decodeSpan is the end of the span of decoding the request body, set by Tracing() (zero if the body cannot be decoded).
+type decodeSpan struct {
+	decoded time.Time
+}
+
+// TraceDecode starts the span for decoding the request body in strictHandler, which ends at the time Tracing() is called.
+// This is the middleware for the operations of oapigen (ChiServerOptions.Middlewares), not for the router.
+func TraceDecode(tp trace.TracerProvider) oapigen.MiddlewareFunc {
+	tracer := tp.Tracer(TracerName)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// not the active span, the spans in the controller are the children of the request span
+			_, span := tracer.Start(r.Context(), "strictHandler.decode")
+			ds := &decodeSpan{}
+			defer func() {
+				if ds.decoded.IsZero() { // Tracing() is not called
+					span.SetStatus(codes.Error, "can't decode request body")
+					span.End()
+					return
+				}
+				span.End(trace.WithTimestamp(ds.decoded))
+			}()
+			ctx := context.WithValue(r.Context(), decodeSpanKey{}, ds)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Tracing marks the end of the decoding span started by TraceDecode(), and sets the operation name to the request span.
+func Tracing() oapigen.StrictMiddlewareFunc {
+	return func(f oapigen.StrictHandlerFunc, operationID string) oapigen.StrictHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			if ds, ok := ctx.Value(decodeSpanKey{}).(*decodeSpan); ok {
+				ds.decoded = time.Now()
+			}
+			span := trace.SpanFromContext(ctx)
+			span.SetAttributes(attribute.String("operation", operationID))
+
+			response, err := f(ctx, w, r, request)
+			if err != nil {
+				span.RecordError(err)
+			}
+			return response, err
+		}
+	}
+}
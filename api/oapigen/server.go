@@ -4,34 +4,111 @@
 package oapigen
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for ExportParamsFormat.
+const (
+	ExportParamsFormatCsv    ExportParamsFormat = "csv"
+	ExportParamsFormatJson   ExportParamsFormat = "json"
+	ExportParamsFormatNdjson ExportParamsFormat = "ndjson"
+)
+
 // Defines values for SuggestJSONBodySort.
 const (
 	SuggestJSONBodySortAsc  SuggestJSONBodySort = "asc"
 	SuggestJSONBodySortDesc SuggestJSONBodySort = "desc"
 )
 
+// BuildInfo the build information of the server
+type BuildInfo struct {
+	// CatalogSize the number of the emoji definitions
+	CatalogSize int `json:"catalogSize"`
+
+	// CatalogVersion the version of the emoji catalog (github.com/enescakir/emoji)
+	CatalogVersion string `json:"catalogVersion"`
+
+	// Revision the VCS revision, if embedded in the binary
+	Revision *string `json:"revision,omitempty"`
+
+	// Version the module version ((devel) if built from the source tree)
+	Version string `json:"version"`
+}
+
 // EmojiDefinition defines model for EmojiDefinition.
 type EmojiDefinition struct {
 	Alias string `json:"alias"`
 	Char  string `json:"char"`
 }
 
+// EmojiPage a page of the emoji catalog
+type EmojiPage struct {
+	// Items the definitions sorted by alias
+	Items []EmojiDefinition `json:"items"`
+
+	// NextOffset the offset of the next page, absent on the last page
+	NextOffset *int `json:"nextOffset,omitempty"`
+
+	// Total the number of the definitions in the catalog
+	Total int `json:"total"`
+}
+
 // Error default error
 type Error struct {
 	Message string `json:"message"`
 }
 
+// Health the status of the server
+type Health struct {
+	Status string `json:"status"`
+}
+
+// ListParams defines parameters for List.
+type ListParams struct {
+	// Offset the number of the definitions to skip
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Limit the number of the definitions in the page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// IfNoneMatch the ETag of the previous response, 304 if not modified
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// ExportParams defines parameters for Export.
+type ExportParams struct {
+	// Format the representation, selected by the Accept header if omitted (default: json)
+	Format *ExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// IfNoneMatch the ETag of the previous response, 304 if not modified
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// ExportParamsFormat defines parameters for Export.
+type ExportParamsFormat string
+
 // SuggestJSONBody defines parameters for Suggest.
 type SuggestJSONBody struct {
+	// Limit the maximum number of the suggestions, 0 means no limit
 	Limit  *int                `json:"limit,omitempty"`
 	Prefix string              `json:"prefix"`
 	Sort   SuggestJSONBodySort `json:"sort"`
@@ -42,6 +119,7 @@ type SuggestJSONBodySort string
 
 // TranslateJSONBody defines parameters for Translate.
 type TranslateJSONBody struct {
+	// Text at most 1000 :<alias>: tokens, and the request body is at most 256KiB
 	Text string `json:"text"`
 }
 
@@ -54,11 +132,26 @@ type TranslateJSONRequestBody TranslateJSONBody
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /emoji)
+	List(w http.ResponseWriter, r *http.Request, params ListParams)
+
+	// (GET /emoji/export)
+	Export(w http.ResponseWriter, r *http.Request, params ExportParams)
+
 	// (POST /emoji/suggest)
 	Suggest(w http.ResponseWriter, r *http.Request)
 
 	// (POST /emoji/translate)
 	Translate(w http.ResponseWriter, r *http.Request)
+
+	// (GET /healthz)
+	Healthz(w http.ResponseWriter, r *http.Request)
+
+	// (GET /readyz)
+	Readyz(w http.ResponseWriter, r *http.Request)
+
+	// (GET /version)
+	Version(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -70,10 +163,128 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
+// List operation middleware
+func (siw *ServerInterfaceWrapper) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.List(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Export operation middleware
+func (siw *ServerInterfaceWrapper) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Export(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // Suggest operation middleware
 func (siw *ServerInterfaceWrapper) Suggest(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.Suggest(w, r)
 	})
@@ -89,6 +300,10 @@ func (siw *ServerInterfaceWrapper) Suggest(w http.ResponseWriter, r *http.Reques
 func (siw *ServerInterfaceWrapper) Translate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.Translate(w, r)
 	})
@@ -100,6 +315,51 @@ func (siw *ServerInterfaceWrapper) Translate(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// Healthz operation middleware
+func (siw *ServerInterfaceWrapper) Healthz(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Healthz(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Readyz operation middleware
+func (siw *ServerInterfaceWrapper) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Readyz(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Version operation middleware
+func (siw *ServerInterfaceWrapper) Version(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Version(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -213,16 +473,175 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/emoji", wrapper.List)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/emoji/export", wrapper.Export)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/emoji/suggest", wrapper.Suggest)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/emoji/translate", wrapper.Translate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/healthz", wrapper.Healthz)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/readyz", wrapper.Readyz)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/version", wrapper.Version)
+	})
 
 	return r
 }
 
+type ListRequestObject struct {
+	Params ListParams
+}
+
+type ListResponseObject interface {
+	VisitListResponse(w http.ResponseWriter) error
+}
+
+type List200ResponseHeaders struct {
+	ETag string
+}
+
+type List200JSONResponse struct {
+	Body    EmojiPage
+	Headers List200ResponseHeaders
+}
+
+func (response List200JSONResponse) VisitListResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type List304ResponseHeaders struct {
+	ETag string
+}
+
+type List304Response struct {
+	Headers List304ResponseHeaders
+}
+
+func (response List304Response) VisitListResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type ListdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListdefaultJSONResponse) VisitListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportRequestObject struct {
+	Params ExportParams
+}
+
+type ExportResponseObject interface {
+	VisitExportResponse(w http.ResponseWriter) error
+}
+
+type Export200ResponseHeaders struct {
+	ETag string
+}
+
+type Export200JSONResponse struct {
+	Body    []EmojiDefinition
+	Headers Export200ResponseHeaders
+}
+
+func (response Export200JSONResponse) VisitExportResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type Export200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	Headers       Export200ResponseHeaders
+	ContentLength int64
+}
+
+func (response Export200ApplicationxNdjsonResponse) VisitExportResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type Export200TextcsvResponse struct {
+	Body          io.Reader
+	Headers       Export200ResponseHeaders
+	ContentLength int64
+}
+
+func (response Export200TextcsvResponse) VisitExportResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type Export304ResponseHeaders struct {
+	ETag string
+}
+
+type Export304Response struct {
+	Headers Export304ResponseHeaders
+}
+
+func (response Export304Response) VisitExportResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type ExportdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ExportdefaultJSONResponse) VisitExportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type SuggestRequestObject struct {
 	Body *SuggestJSONRequestBody
 }
@@ -240,6 +659,44 @@ func (response Suggest200JSONResponse) VisitSuggestResponse(w http.ResponseWrite
 	return json.NewEncoder(w).Encode(response)
 }
 
+type Suggest200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response Suggest200ApplicationxNdjsonResponse) VisitSuggestResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type Suggest200TextcsvResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response Suggest200TextcsvResponse) VisitSuggestResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
 type SuggestdefaultJSONResponse struct {
 	Body       Error
 	StatusCode int
@@ -281,14 +738,113 @@ func (response TranslatedefaultJSONResponse) VisitTranslateResponse(w http.Respo
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type HealthzRequestObject struct {
+}
+
+type HealthzResponseObject interface {
+	VisitHealthzResponse(w http.ResponseWriter) error
+}
+
+type Healthz200JSONResponse Health
+
+func (response Healthz200JSONResponse) VisitHealthzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type HealthzdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response HealthzdefaultJSONResponse) VisitHealthzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReadyzRequestObject struct {
+}
+
+type ReadyzResponseObject interface {
+	VisitReadyzResponse(w http.ResponseWriter) error
+}
+
+type Readyz200JSONResponse Health
+
+func (response Readyz200JSONResponse) VisitReadyzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReadyzdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ReadyzdefaultJSONResponse) VisitReadyzResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type VersionRequestObject struct {
+}
+
+type VersionResponseObject interface {
+	VisitVersionResponse(w http.ResponseWriter) error
+}
+
+type Version200JSONResponse BuildInfo
+
+func (response Version200JSONResponse) VisitVersionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type VersiondefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response VersiondefaultJSONResponse) VisitVersionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 
+	// (GET /emoji)
+	List(ctx context.Context, request ListRequestObject) (ListResponseObject, error)
+
+	// (GET /emoji/export)
+	Export(ctx context.Context, request ExportRequestObject) (ExportResponseObject, error)
+
 	// (POST /emoji/suggest)
 	Suggest(ctx context.Context, request SuggestRequestObject) (SuggestResponseObject, error)
 
 	// (POST /emoji/translate)
 	Translate(ctx context.Context, request TranslateRequestObject) (TranslateResponseObject, error)
+
+	// (GET /healthz)
+	Healthz(ctx context.Context, request HealthzRequestObject) (HealthzResponseObject, error)
+
+	// (GET /readyz)
+	Readyz(ctx context.Context, request ReadyzRequestObject) (ReadyzResponseObject, error)
+
+	// (GET /version)
+	Version(ctx context.Context, request VersionRequestObject) (VersionResponseObject, error)
 }
 
 type StrictHandlerFunc = runtime.StrictHttpHandlerFunc
@@ -320,6 +876,58 @@ type strictHandler struct {
 	options     StrictHTTPServerOptions
 }
 
+// List operation middleware
+func (sh *strictHandler) List(w http.ResponseWriter, r *http.Request, params ListParams) {
+	var request ListRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.List(ctx, request.(ListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "List")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListResponseObject); ok {
+		if err := validResponse.VisitListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Export operation middleware
+func (sh *strictHandler) Export(w http.ResponseWriter, r *http.Request, params ExportParams) {
+	var request ExportRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Export(ctx, request.(ExportRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Export")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportResponseObject); ok {
+		if err := validResponse.VisitExportResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
 // Suggest operation middleware
 func (sh *strictHandler) Suggest(w http.ResponseWriter, r *http.Request) {
 	var request SuggestRequestObject
@@ -381,3 +989,181 @@ func (sh *strictHandler) Translate(w http.ResponseWriter, r *http.Request) {
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
 	}
 }
+
+// Healthz operation middleware
+func (sh *strictHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	var request HealthzRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Healthz(ctx, request.(HealthzRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Healthz")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(HealthzResponseObject); ok {
+		if err := validResponse.VisitHealthzResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Readyz operation middleware
+func (sh *strictHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	var request ReadyzRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Readyz(ctx, request.(ReadyzRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Readyz")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReadyzResponseObject); ok {
+		if err := validResponse.VisitReadyzResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Version operation middleware
+func (sh *strictHandler) Version(w http.ResponseWriter, r *http.Request) {
+	var request VersionRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Version(ctx, request.(VersionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Version")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(VersionResponseObject); ok {
+		if err := validResponse.VisitVersionResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xZ3W4bTxV/ldHARSJt4k3TPyp7l7SVGlraiFQRUsjFePfYnmZ3Zjsz63pTWcKRoEVI",
+	"FAWJqgKp5UMCURVuuOCG9mHclHLHI6Azs2t77XWcVP1ANFfe9cycr985vzOz85CGMkmlAGE0DR5SHXYg",
+	"YfZxM+NxtCVaEl9YFHHDpWDxtpIpKMNB06DFYg0ejUCHiqc4TgNqOkCauJZw0ZIqYfg/kS2CAxpUFxT1",
+	"aDoh5iENmWGxbO/wQ8DXWYEiS5qgSimQyHucRNDiwlqlqUdNngINKBcG2qBo3yuF7oLSVlCd3K4brAou",
+	"FpKlNjedrLkayqQBAnTIDrhq2DnL1KPQY0kao9Lu2qq/6o+N0EZx0UYbFHT5fO27V3dIOcMjvEUgaUIU",
+	"AYbO2tPkgqm8ouu7zfAywKU6Zd3TPE1klMVjh5eWIuhCvIxaES1DWkomDiOZqRCIUQBTbvqra3VuWj/v",
+	"Z1xBRIO9kRleBdYZPPZHcmTzHoQGPbiOsb02wnVh5lWziMXcpe7Y5CDih4d5UBetsMNUdfJ/nh+/XOid",
+	"U1Isn+vDNmvDOeuGkZS1oTYTZ+qFG0h0Pc4TVUG0VAYi0sxJafVo4bcVtGhAv9UY13+jKP7GNAr9kZdM",
+	"KZbju4CeudNqaTD1Vkg7VjqDs613HmFNDcIQ6fI7ZtoN1BawkYbFZ+GDSZ+LyhlHblruFJ4uIqWyWkCV",
+	"kuqcYEbQYllsCNi10/AloHWRIKcnWzmxzqwbwGLT+QBy1oaZTC9gZDepWh/yYGF1FMtm7e17VEOYKW7y",
+	"HUyzomJTfhPyjcz5MWvqxvYWOYC8BPWHKxvbWys3IScdYJG1muPU0ZtgCSodzRvb6zRh3JrAFKjFOpl2",
+	"FGynEyMPQJAlXCYVP7RNLSCbbvBHme+vhweQ2wfLmraWULFbPzakY0xK+xgPXvTWqgmu8De2t3AJNzFM",
+	"/TcieerbptP3qExBsJTTgK6v+qvriCUzHRtg163wqT2vVKs9b4oyPFud9r0oaSYiEvOEG7L0gJuOjdH1",
+	"u6xd5lMhCGOA+WQDtRXRgN7i2ljTFEvAgNI02DtfaRtJ9AFPS9TvZ6DyMejOvjL0zDlrq5AGvkcTLniS",
+	"JfZ5lhI+iGMK4qozxkao3pY1H61hPWfNmu9PGrd2VuMmQ57iHkJmmijQqRQaPLLuX8bOLqTBxs9bHKJ5",
+	"xbLVWrktBax8n5mwUzF5utT3PVpqsMl1yffxJ5TCgDCuntOYhxbzxj3tGvhY3sKeY7umrY3ztEbnkLUI",
+	"o1Kf5ZPxUpAqwDZkDfVISypy9iigeev+5Vk1k8EmSxWBhGuS4ANEpFI1y1/G/lEyfiz0bJurQa7aBye7",
+	"gK3+SS7e28dMn+wIe/uYcoa1kSocCWJn6a2UHWdFhzIFO6qARXQfFTjKa0AvlcrMZT4Wx7PHiCkOwzr/",
+	"3s6d2x65fQ1/iVTk6s7uAuJzeIQs7HDRrogrNj1hzDHg0/x43Rl8Boacxl9DDGHB2raDhSGkpuiRSAMy",
+	"4QbHlwo4AoLwLs/hLndoq+QQCCSnPYrLcGJUPIS6O9Hrx3uC/xfG+jjb5b5XUdNbEdGsqpkYUgM908AI",
+	"nzpvpuQuGPGCEWsZUWftNmjrVyp1DSee/OTxv1+8evuPH79/9Pfh4E8nf3t98ua3w8Gz4dHP3/360cmr",
+	"pyePnw6Pjt/94vfDwbMZ/topxLsDAWizKaP8XPE8x1Hf7a/qP3S4rdXU9q1wHlneIz5JgAlNhCTlRu20",
+	"7SEqhxbv1eSOR3XRZUYJRJkOqTeiTPeGZtYyZeX0VKgphNYcoirzjcqg/zUS3NdRrkYxoWNmYH7BBu7g",
+	"aU9q9hGC4eCvw6PHw8FPh4O/vP/dn//15PXw6Pjkly+HR78aDl5MlrHVMjw6fvvPN8PBH4aD5+PBwcuT",
+	"P/7s3ZPfuNqfqfS7I8s+S61jmsz6zrC1aEPwAEVqAuFO7NqzB1bXI6ylpCmjHLtPKeDSN9+5yTepPZPd",
+	"AtFG4FCov6harV2fo0gvKqJjv3Udzt3Nx7wLArQmqZJN/MoYP2C5JvKAPOjwGCY+dCHyKhMCIzmd1zcK",
+	"LZ/wmOtU1IV17pe5L4ttCZXOtYGkgAORyeejgcN8Eo5v/HWSCcPjmkuWDtOkCSDcLcQMJj9wqi4gWQDJ",
+	"xN3P3C991Ssgr3L9NKLJKjqaH0JjfKFTxWZ39P8nA2d8CTkHn0U3jf9rUFm6RMvqDvexDFlM7MWcTBP3",
+	"kSBTcfHVOGg07ISO1Ca44l/xaX+//98BAL3V5q/BHQAA",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	var res = make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	var resolvePath = PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		var pathToFile = url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,150 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: emoji/v1/emoji.proto
+
+package emojiv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	EmojiService_Translate_FullMethodName = "/emoji.v1.EmojiService/Translate"
+	EmojiService_Suggest_FullMethodName   = "/emoji.v1.EmojiService/Suggest"
+)
+
+// EmojiServiceClient is the client API for EmojiService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EmojiServiceClient interface {
+	// Translate converts the aliases in the text (e.g. ":dizzy:") to the emoji.
+	Translate(ctx context.Context, in *TranslateRequest, opts ...grpc.CallOption) (*TranslateResponse, error)
+	// Suggest returns the emoji definitions whose alias starts with the prefix.
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error)
+}
+
+type emojiServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEmojiServiceClient(cc grpc.ClientConnInterface) EmojiServiceClient {
+	return &emojiServiceClient{cc}
+}
+
+func (c *emojiServiceClient) Translate(ctx context.Context, in *TranslateRequest, opts ...grpc.CallOption) (*TranslateResponse, error) {
+	out := new(TranslateResponse)
+	err := c.cc.Invoke(ctx, EmojiService_Translate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emojiServiceClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error) {
+	out := new(SuggestResponse)
+	err := c.cc.Invoke(ctx, EmojiService_Suggest_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EmojiServiceServer is the server API for EmojiService service.
+// All implementations must embed UnimplementedEmojiServiceServer
+// for forward compatibility
+type EmojiServiceServer interface {
+	// Translate converts the aliases in the text (e.g. ":dizzy:") to the emoji.
+	Translate(context.Context, *TranslateRequest) (*TranslateResponse, error)
+	// Suggest returns the emoji definitions whose alias starts with the prefix.
+	Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error)
+	mustEmbedUnimplementedEmojiServiceServer()
+}
+
+// UnimplementedEmojiServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEmojiServiceServer struct {
+}
+
+func (UnimplementedEmojiServiceServer) Translate(context.Context, *TranslateRequest) (*TranslateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Translate not implemented")
+}
+func (UnimplementedEmojiServiceServer) Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedEmojiServiceServer) mustEmbedUnimplementedEmojiServiceServer() {}
+
+// UnsafeEmojiServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EmojiServiceServer will
+// result in compilation errors.
+type UnsafeEmojiServiceServer interface {
+	mustEmbedUnimplementedEmojiServiceServer()
+}
+
+func RegisterEmojiServiceServer(s grpc.ServiceRegistrar, srv EmojiServiceServer) {
+	s.RegisterService(&EmojiService_ServiceDesc, srv)
+}
+
+func _EmojiService_Translate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TranslateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmojiServiceServer).Translate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmojiService_Translate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmojiServiceServer).Translate(ctx, req.(*TranslateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmojiService_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmojiServiceServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmojiService_Suggest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmojiServiceServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EmojiService_ServiceDesc is the grpc.ServiceDesc for EmojiService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EmojiService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "emoji.v1.EmojiService",
+	HandlerType: (*EmojiServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Translate",
+			Handler:    _EmojiService_Translate_Handler,
+		},
+		{
+			MethodName: "Suggest",
+			Handler:    _EmojiService_Suggest_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "emoji/v1/emoji.proto",
+}
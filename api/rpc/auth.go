@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	emojiv1 "github.com/podhmo/emoji-api/api/pb/emoji/v1"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/logging"
+	"github.com/podhmo/emoji-api/seed/design"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Scopes is the scopes of the API key required by the methods (the same scopes as the operations in seed/design/action).
+var Scopes = map[string][]string{
+	emojiv1.EmojiService_Translate_FullMethodName: {design.ScopeRead},
+	emojiv1.EmojiService_Suggest_FullMethodName:   {design.ScopeRead},
+}
+
+// AuthInterceptor authenticates the API key (authorization: Bearer <key> or x-api-key metadata) of the methods in Scopes.
+// It is included in ServerOptions() if Config.KeyStore is set.
+//
+//	rpc.NewServer(grpc.UnaryInterceptor(rpc.AuthInterceptor(store)))
+func AuthInterceptor(store auth.KeyStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scopes, ok := Scopes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		key := apiKey(ctx)
+		if key == "" {
+			return nil, status.Error(codes.Unauthenticated, "API key is required")
+		}
+		k, err := store.Lookup(ctx, key)
+		if err != nil {
+			if !errors.Is(err, auth.ErrUnknownKey) {
+				logging.FromContext(ctx).ErrorContext(ctx, "key store failed", "error", err)
+			}
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		if !k.HasScopes(scopes...) {
+			return nil, status.Errorf(codes.PermissionDenied, "insufficient scope, required: %s", strings.Join(scopes, ", "))
+		}
+		return handler(auth.WithKey(ctx, k), req)
+	}
+}
+
+func apiKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vs := md.Get("x-api-key"); len(vs) > 0 && vs[0] != "" {
+		return vs[0]
+	}
+	if vs := md.Get("authorization"); len(vs) > 0 {
+		if token, ok := strings.CutPrefix(vs[0], "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
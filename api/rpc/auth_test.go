@@ -0,0 +1,49 @@
+package rpc_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	emojiv1 "github.com/podhmo/emoji-api/api/pb/emoji/v1"
+	"github.com/podhmo/emoji-api/api/rpc"
+	"github.com/podhmo/emoji-api/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	if err := os.WriteFile(path, []byte("keys:\n  - name: reader\n    key: read-key\n    scopes: [read]\n  - name: writer\n    key: write-key\n    scopes: [write]\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	store, err := auth.LoadFileKeyStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	client := newClient(t, grpc.UnaryInterceptor(rpc.AuthInterceptor(store)))
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "no-key", wantCode: codes.Unauthenticated},
+		{name: "unknown-key", md: metadata.Pairs("x-api-key", "unknown"), wantCode: codes.Unauthenticated},
+		{name: "insufficient-scope", md: metadata.Pairs("x-api-key", "write-key"), wantCode: codes.PermissionDenied},
+		{name: "api-key", md: metadata.Pairs("x-api-key", "read-key"), wantCode: codes.OK},
+		{name: "bearer", md: metadata.Pairs("authorization", "Bearer read-key"), wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			_, err := client.Translate(ctx, &emojiv1.TranslateRequest{Text: "hmm :dizzy:"})
+			if want, got := tt.wantCode, status.Code(err); want != got {
+				t.Errorf("code: want=%v, but got=%v (%v)", want, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Package rpc is the gRPC service mirroring the HTTP API (api.EmojiController).
+package rpc
+
+import (
+	"context"
+
+	emojiv1 "github.com/podhmo/emoji-api/api/pb/emoji/v1"
+	"github.com/podhmo/emoji-api/emojilib"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type EmojiService struct {
+	emojiv1.UnimplementedEmojiServiceServer
+}
+
+func NewEmojiService() *EmojiService {
+	return &EmojiService{}
+}
+
+// NewServer returns the gRPC server, the EmojiService is registered.
+func NewServer(options ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(options...)
+	emojiv1.RegisterEmojiServiceServer(s, NewEmojiService())
+	return s
+}
+
+// Translate is the same as POST /emoji/translate
+func (s *EmojiService) Translate(ctx context.Context, req *emojiv1.TranslateRequest) (*emojiv1.TranslateResponse, error) {
+	return &emojiv1.TranslateResponse{Text: emojilib.Translate(req.Text)}, nil
+}
+
+// Suggest is the same as POST /emoji/suggest
+func (s *EmojiService) Suggest(ctx context.Context, req *emojiv1.SuggestRequest) (*emojiv1.SuggestResponse, error) {
+	option := emojilib.SuggestOption{}
+	if limit := req.Limit; limit != nil {
+		if *limit < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "limit must not be negative: %d", *limit)
+		}
+		option.Limit = int(*limit)
+	}
+	if req.Sort == emojiv1.Sort_SORT_DESC {
+		option.Reverse = true
+	}
+
+	suggestions := emojilib.Suggest(req.Prefix, option)
+	definitions := make([]*emojiv1.EmojiDefinition, len(suggestions))
+	for i, x := range suggestions {
+		definitions[i] = &emojiv1.EmojiDefinition{
+			Alias: x.Alias,
+			Char:  x.Char,
+		}
+	}
+	return &emojiv1.SuggestResponse{Definitions: definitions}, nil
+}
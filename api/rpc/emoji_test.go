@@ -0,0 +1,119 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	emojiv1 "github.com/podhmo/emoji-api/api/pb/emoji/v1"
+	"github.com/podhmo/emoji-api/api/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func newClient(t *testing.T, options ...grpc.ServerOption) emojiv1.EmojiServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := rpc.NewServer(options...)
+	go s.Serve(lis) // nolint
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %+v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return emojiv1.NewEmojiServiceClient(conn)
+}
+
+func TestTranslate(t *testing.T) {
+	client := newClient(t)
+
+	res, err := client.Translate(context.Background(), &emojiv1.TranslateRequest{Text: "hmm :dizzy:"})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if want, got := "hmm 💫", res.Text; want != got {
+		t.Errorf("Translate() mismatch: want=%q, but got=%q", want, got)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	client := newClient(t)
+
+	tests := []struct {
+		name string
+		req  *emojiv1.SuggestRequest
+		want []*emojiv1.EmojiDefinition
+	}{
+		{name: "prefix", req: &emojiv1.SuggestRequest{Prefix: ":diz"},
+			want: []*emojiv1.EmojiDefinition{{Alias: ":dizzy:", Char: "💫"}, {Alias: ":dizzy_face:", Char: "😵"}}},
+		{name: "limit", req: &emojiv1.SuggestRequest{Prefix: ":smil", Limit: proto.Int32(2)},
+			want: []*emojiv1.EmojiDefinition{{Alias: ":smile:", Char: "😄"}, {Alias: ":smile_cat:", Char: "😸"}}},
+		{name: "desc", req: &emojiv1.SuggestRequest{Prefix: ":smil", Limit: proto.Int32(1), Sort: emojiv1.Sort_SORT_DESC},
+			want: []*emojiv1.EmojiDefinition{{Alias: ":smiling_imp:", Char: "😈"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.Suggest(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if diff := cmp.Diff(tt.want, res.Definitions, protocmp.Transform()); diff != "" {
+				t.Errorf("Suggest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSuggestNegativeLimit(t *testing.T) {
+	client := newClient(t)
+
+	_, err := client.Suggest(context.Background(), &emojiv1.SuggestRequest{Prefix: ":diz", Limit: proto.Int32(-1)})
+	if want, got := codes.InvalidArgument, status.Code(err); want != got {
+		t.Errorf("status code: want=%v, but got=%v (%+v)", want, got, err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok")) // nolint
+	})
+	ts := httptest.NewServer(rpc.Handler(rpc.NewServer(), httpHandler))
+	t.Cleanup(ts.Close)
+
+	// gRPC (h2c)
+	conn, err := grpc.Dial(ts.Listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %+v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	res, err := emojiv1.NewEmojiServiceClient(conn).Translate(context.Background(), &emojiv1.TranslateRequest{Text: ":dizzy:"})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if want, got := "💫", res.Text; want != got {
+		t.Errorf("Translate() mismatch: want=%q, but got=%q", want, got)
+	}
+
+	// HTTP/1.1
+	httpRes, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	defer httpRes.Body.Close()
+	if want, got := http.StatusOK, httpRes.StatusCode; want != got {
+		t.Errorf("status code: want=%d, but got=%d", want, got)
+	}
+}
@@ -0,0 +1,24 @@
+package rpc
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// Handler serves the gRPC requests and the HTTP requests on one port, via h2c (HTTP/2 without TLS).
+//
+//	http.ListenAndServe(":8080", rpc.Handler(rpc.NewServer(), httpHandler))
+func Handler(grpcServer *grpc.Server, httpHandler http.Handler) http.Handler {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+	return h2c.NewHandler(h, &http2.Server{})
+}
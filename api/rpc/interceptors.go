@@ -0,0 +1,273 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"path"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/podhmo/emoji-api/api/middleware"
+	emojiv1 "github.com/podhmo/emoji-api/api/pb/emoji/v1"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/emojilib"
+	"github.com/podhmo/emoji-api/logging"
+	"github.com/podhmo/emoji-api/metrics"
+	"github.com/podhmo/emoji-api/ratelimit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Config is the configuration of the interceptors, the same as server.Config of the HTTP handler.
+type Config struct {
+	Metrics        *metrics.Registry    // default: metrics.Default
+	TracerProvider trace.TracerProvider // default: otel.GetTracerProvider()
+	Logger         *slog.Logger         // default: slog.Default()
+
+	RateLimits     map[string]ratelimit.Limit // the limit for each method (e.g. "Suggest"), shared with the HTTP operations of the same name
+	RateLimitStore ratelimit.Store            // default: ratelimit.NewMemoryStore(), pass the store of the HTTP handler to share the buckets
+
+	KeyStore auth.KeyStore // the API keys of the methods in Scopes, the auth is disabled if nil
+
+	Limits middleware.LimitsConfig // the message size (as MaxBodyBytes), the text length and the alias tokens of Translate (default if zero)
+}
+
+// ServerOptions returns the interceptors of config, in the same order as the middlewares of the HTTP handler.
+//
+//	tracing -> logging -> metrics -> auth -> rate limit -> limits -> the method
+func ServerOptions(config Config) []grpc.ServerOption {
+	config.Limits = config.Limits.WithDefaults()
+	if config.Metrics == nil {
+		config.Metrics = metrics.Default
+	}
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.RateLimitStore == nil {
+		config.RateLimitStore = ratelimit.NewMemoryStore()
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		TracingInterceptor(config.TracerProvider),
+		LoggingInterceptor(config.Logger),
+		MetricsInterceptor(config.Metrics),
+	}
+	if config.KeyStore != nil {
+		interceptors = append(interceptors, AuthInterceptor(config.KeyStore))
+	}
+	interceptors = append(interceptors,
+		RateLimitInterceptor(config.RateLimits, config.RateLimitStore),
+		LimitsInterceptor(config.Limits),
+	)
+
+	maxRecvMsgSize := math.MaxInt32
+	if n := config.Limits.MaxBodyBytes; n >= 0 && n < math.MaxInt32 {
+		maxRecvMsgSize = int(n)
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptors...), // the first one is the outermost
+		grpc.MaxRecvMsgSize(maxRecvMsgSize),         // ResourceExhausted if exceeded
+	}
+}
+
+// methodName returns the short name of the method (e.g. "Suggest" for /emoji.v1.EmojiService/Suggest).
+func methodName(fullMethod string) string {
+	return path.Base(fullMethod)
+}
+
+// TracingInterceptor starts the span for each call (the parent is extracted from the traceparent metadata).
+func TracingInterceptor(tp trace.TracerProvider) grpc.UnaryServerInterceptor {
+	tracer := tp.Tracer(TracerName)
+	propagator := propagation.TraceContext{}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = propagator.Extract(ctx, metadataCarrier(md))
+		ctx, span := tracer.Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", info.FullMethod),
+				attribute.String("operation", methodName(info.FullMethod)),
+			),
+		)
+		defer span.End()
+
+		res, err := handler(ctx, req)
+		code := status.Code(err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+		if err != nil {
+			span.RecordError(err)
+			if serverError(code) {
+				span.SetStatus(otelcodes.Error, code.String())
+			}
+		}
+		return res, err
+	}
+}
+
+// TracerName is the instrumentation name of the spans started by this package.
+const TracerName = "github.com/podhmo/emoji-api/api/rpc"
+
+// metadataCarrier is the propagation.TextMapCarrier of the incoming metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if vs := metadata.MD(c).Get(key); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// LoggingInterceptor writes the access log for each call, and carries the logger having the operation in the context.
+//
+//	level=INFO msg=rpc method=/emoji.v1.EmojiService/Suggest operation=Suggest code=OK latency=...
+func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		l := logger.With("operation", methodName(info.FullMethod))
+		ctx = logging.WithLogger(ctx, l)
+
+		res, err := handler(ctx, req)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch {
+		case serverError(code):
+			level = slog.LevelError
+		case err != nil:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Duration("latency", time.Since(start)),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+		}
+		l.LogAttrs(ctx, level, "rpc", attrs...)
+		return res, err
+	}
+}
+
+// MetricsInterceptor counts the calls, the errors and the latency for each method.
+//
+//	emoji_rpc_requests_total{method="Suggest",code="OK"} 1
+//	emoji_rpc_errors_total{method="Suggest"} 0
+//	emoji_rpc_request_duration_seconds_bucket{method="Suggest",le="0.005"} 1
+func MetricsInterceptor(registry *metrics.Registry) grpc.UnaryServerInterceptor {
+	requests := registry.NewCounterVec("emoji_rpc_requests_total", "the number of gRPC calls for each method", "method", "code")
+	errors := registry.NewCounterVec("emoji_rpc_errors_total", "the number of gRPC calls failed (code != OK) for each method", "method")
+	latency := registry.NewHistogramVec("emoji_rpc_request_duration_seconds", "the latency of the gRPC calls", metrics.DefBuckets, "method")
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := methodName(info.FullMethod)
+		start := time.Now()
+		res, err := handler(ctx, req)
+		latency.With(method).Observe(time.Since(start).Seconds())
+
+		requests.With(method, status.Code(err).String()).Inc()
+		if err != nil {
+			errors.With(method).Inc()
+		}
+		return res, err
+	}
+}
+
+// RateLimitInterceptor limits the calls for each method and client with the token bucket, the same as middleware.RateLimit().
+// The buckets are keyed as the HTTP operations ("Suggest:ip:192.0.2.1"), then a client cannot double the limit via gRPC
+// if the store is shared. If the bucket is empty, ResourceExhausted is returned with the retry-after header (in seconds).
+// The calls are allowed if the store fails (fail open).
+func RateLimitInterceptor(limits map[string]ratelimit.Limit, store ratelimit.Store) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := methodName(info.FullMethod)
+		limit, ok := limits[method]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		result, err := store.Take(ctx, method+":"+clientKey(ctx), limit, time.Now())
+		if err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "rate limit store failed", "operation", method, "error", err)
+			return handler(ctx, req)
+		}
+		if !result.Allowed {
+			retryAfter := strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds())))
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter)) // nolint
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ss", retryAfter)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// LimitsInterceptor rejects the request exceeding the limits with InvalidArgument, the same as middleware.Limits().
+//
+//   - Translate: the length of the text, and the number of the :<alias>: tokens
+func LimitsInterceptor(config middleware.LimitsConfig) grpc.UnaryServerInterceptor {
+	config = config.WithDefaults()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		switch req := req.(type) {
+		case *emojiv1.TranslateRequest:
+			text := req.GetText()
+			if n, max := utf8.RuneCountInString(text), config.MaxTextLength; max >= 0 && n > max {
+				return nil, status.Errorf(codes.InvalidArgument, "text is too long: %d characters (max %d)", n, max)
+			}
+			if n, max := emojilib.CountAliases(text), config.MaxAliasTokens; max >= 0 && n > max {
+				return nil, status.Errorf(codes.InvalidArgument, "too many :<alias>: tokens: %d (max %d)", n, max)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// clientKey returns the key of the client, the authenticated API key (set by AuthInterceptor()) or the peer IP,
+// in the same format as middleware.ClientKey().
+func clientKey(ctx context.Context) string {
+	if k, ok := auth.KeyFromContext(ctx); ok && len(k.SHA256) >= 16 {
+		return "key:" + k.SHA256[:16]
+	}
+
+	host := "unknown"
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host = p.Addr.String()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+	return "ip:" + host
+}
+
+// serverError reports whether the code is the failure of the server (like 5xx of HTTP).
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,146 @@
+package rpc_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/podhmo/emoji-api/api/middleware"
+	emojiv1 "github.com/podhmo/emoji-api/api/pb/emoji/v1"
+	"github.com/podhmo/emoji-api/api/rpc"
+	"github.com/podhmo/emoji-api/metrics"
+	"github.com/podhmo/emoji-api/ratelimit"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerOptions(t *testing.T) {
+	var buf bytes.Buffer
+	registry := metrics.NewRegistry()
+	exporter := tracetest.NewInMemoryExporter()
+	store := ratelimit.NewMemoryStore()
+	client := newClient(t, rpc.ServerOptions(rpc.Config{
+		Metrics:        registry,
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		Logger:         slog.New(slog.NewJSONHandler(&buf, nil)),
+		RateLimits:     map[string]ratelimit.Limit{"Suggest": {Rate: 0.5, Burst: 1}}, // 1 token per 2s
+		RateLimitStore: store,
+	})...)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	if _, err := client.Suggest(ctx, &emojiv1.SuggestRequest{Prefix: ":diz"}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	var header metadata.MD
+	_, err := client.Suggest(ctx, &emojiv1.SuggestRequest{Prefix: ":diz"}, grpc.Header(&header))
+	if want, got := codes.ResourceExhausted, status.Code(err); want != got {
+		t.Fatalf("code: want=%v, but got=%v (%v)", want, got, err)
+	}
+	if want, got := []string{"2"}, header.Get("retry-after"); len(got) != 1 || want[0] != got[0] {
+		t.Errorf("retry-after: want=%q, but got=%q", want, got)
+	}
+	if _, err := client.Translate(ctx, &emojiv1.TranslateRequest{Text: ":dizzy:"}); err != nil { // not limited
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	t.Run("metrics", func(t *testing.T) {
+		var out bytes.Buffer
+		if _, err := registry.WriteTo(&out); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		for _, line := range []string{
+			`emoji_rpc_requests_total{method="Suggest",code="OK"} 1`,
+			`emoji_rpc_requests_total{method="Suggest",code="ResourceExhausted"} 1`,
+			`emoji_rpc_requests_total{method="Translate",code="OK"} 1`,
+			`emoji_rpc_errors_total{method="Suggest"} 1`,
+		} {
+			if !strings.Contains(out.String(), line+"\n") {
+				t.Errorf("want %q in the metrics, but not found\n%s", line, out.String())
+			}
+		}
+	})
+
+	t.Run("logging", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if want, got := 3, len(lines); want != got {
+			t.Fatalf("the number of the log lines: want=%d, but got=%d\n%s", want, got, buf.String())
+		}
+		for i, want := range []string{`"level":"INFO"`, `"level":"WARN"`, `"level":"INFO"`} {
+			if !strings.Contains(lines[i], want) || !strings.Contains(lines[i], `"msg":"rpc"`) {
+				t.Errorf("%d: want %s in %s", i, want, lines[i])
+			}
+		}
+		if want := `"code":"ResourceExhausted"`; !strings.Contains(lines[1], want) {
+			t.Errorf("want %s in %s", want, lines[1])
+		}
+	})
+
+	t.Run("tracing", func(t *testing.T) {
+		spans := exporter.GetSpans()
+		if want, got := 3, len(spans); want != got {
+			t.Fatalf("the number of the spans: want=%d, but got=%d", want, got)
+		}
+		span := spans[0]
+		if want, got := emojiv1.EmojiService_Suggest_FullMethodName, span.Name; want != got {
+			t.Errorf("span name: want=%q, but got=%q", want, got)
+		}
+		if want, got := traceID, span.SpanContext.TraceID().String(); want != got {
+			t.Errorf("trace id: want=%q, but got=%q", want, got)
+		}
+		found := false
+		for _, attr := range span.Attributes {
+			found = found || attr == attribute.String("operation", "Suggest")
+		}
+		if !found {
+			t.Errorf("operation attribute is not found: %v", span.Attributes)
+		}
+	})
+
+	t.Run("shared-store", func(t *testing.T) {
+		// the bucket is shared with the HTTP operation of the same name (middleware.RateLimit())
+		result, err := store.Take(context.Background(), "Suggest:ip:bufconn", ratelimit.Limit{Rate: 0.5, Burst: 1}, time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if result.Allowed {
+			t.Errorf("the bucket of the gRPC client should be empty")
+		}
+	})
+}
+
+func TestLimits(t *testing.T) {
+	client := newClient(t, rpc.ServerOptions(rpc.Config{
+		Metrics: metrics.NewRegistry(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Limits:  middleware.LimitsConfig{MaxBodyBytes: 1024, MaxTextLength: 20, MaxAliasTokens: 2},
+	})...)
+
+	tests := []struct {
+		name     string
+		text     string
+		wantCode codes.Code
+	}{
+		{name: "ok", text: "hmm :dizzy: :smile:", wantCode: codes.OK},
+		{name: "too-long", text: strings.Repeat("x", 21), wantCode: codes.InvalidArgument},
+		{name: "too-many-aliases", text: ":a: :b: :c:", wantCode: codes.InvalidArgument},
+		{name: "too-large-message", text: strings.Repeat("x", 2048), wantCode: codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Translate(context.Background(), &emojiv1.TranslateRequest{Text: tt.text})
+			if want, got := tt.wantCode, status.Code(err); want != got {
+				t.Errorf("code: want=%v, but got=%v (%v)", want, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+func TestAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	keys := `
+keys:
+  - name: reader
+    key: read-key
+    scopes: [read]
+  - name: writer
+    key: write-key
+    scopes: [write]
+`
+	if err := os.WriteFile(path, []byte(keys), 0600); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	store, err := auth.LoadFileKeyStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{Metrics: metrics.NewRegistry(), KeyStore: store})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{name: "no-key", method: "POST", path: "/emoji/suggest", wantStatus: http.StatusUnauthorized},
+		{name: "no-key-translate", method: "POST", path: "/emoji/translate", wantStatus: http.StatusUnauthorized},
+		{name: "unknown-key", method: "POST", path: "/emoji/suggest", header: map[string]string{"X-API-Key": "unknown"}, wantStatus: http.StatusUnauthorized},
+		{name: "not-bearer", method: "POST", path: "/emoji/suggest", header: map[string]string{"Authorization": "Basic cmVhZC1rZXk6"}, wantStatus: http.StatusUnauthorized},
+		{name: "insufficient-scope", method: "POST", path: "/emoji/suggest", header: map[string]string{"X-API-Key": "write-key"}, wantStatus: http.StatusForbidden},
+		{name: "api-key", method: "POST", path: "/emoji/suggest", header: map[string]string{"X-API-Key": "read-key"}, wantStatus: http.StatusOK},
+		{name: "bearer", method: "POST", path: "/emoji/translate", header: map[string]string{"Authorization": "Bearer read-key"}, wantStatus: http.StatusOK},
+		{name: "no-security", method: "GET", path: "/healthz", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"prefix": ":diz"}`
+			if strings.HasSuffix(tt.path, "/translate") {
+				body = `{"text": ":dizzy:"}`
+			}
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			if res.StatusCode == http.StatusOK {
+				return
+			}
+
+			if want, got := "application/json", res.Header.Get("Content-Type"); want != got {
+				t.Errorf("content-type: want=%q, but got=%q", want, got)
+			}
+			if got := res.Header.Get("WWW-Authenticate"); !strings.HasPrefix(got, "Bearer ") {
+				t.Errorf("WWW-Authenticate: want Bearer challenge, but got=%q", got)
+			}
+			var got struct {
+				Message string `json:"message"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if got.Message == "" {
+				t.Errorf("message should not be empty")
+			}
+		})
+	}
+}
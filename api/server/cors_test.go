@@ -0,0 +1,107 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/middleware"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+func TestCORS(t *testing.T) {
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{
+		Metrics: metrics.NewRegistry(),
+		CORS: middleware.CORSConfig{
+			AllowedOrigins: []string{"https://picker.example.com", "https://*.example.net"},
+			MaxAge:         time.Hour,
+		},
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string]string
+		wantStatus int
+		wantHeader map[string]string // "" means the header should be absent
+	}{
+		{name: "preflight", method: "OPTIONS", path: "/emoji/suggest",
+			header:     map[string]string{"Origin": "https://picker.example.com", "Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "content-type, x-api-key"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":  "https://picker.example.com",
+				"Access-Control-Allow-Methods": "POST",
+				"Access-Control-Allow-Headers": "content-type, x-api-key",
+				"Access-Control-Max-Age":       "3600",
+			},
+		},
+		{name: "preflight-get", method: "OPTIONS", path: "/healthz",
+			header:     map[string]string{"Origin": "https://a.example.net", "Access-Control-Request-Method": "GET"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "https://a.example.net", "Access-Control-Allow-Methods": "GET", "Access-Control-Allow-Headers": ""},
+		},
+		{name: "preflight-disallowed-origin", method: "OPTIONS", path: "/emoji/suggest",
+			header:     map[string]string{"Origin": "https://evil.example.org", "Access-Control-Request-Method": "POST"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+		},
+		{name: "preflight-disallowed-header", method: "OPTIONS", path: "/emoji/suggest",
+			header:     map[string]string{"Origin": "https://picker.example.com", "Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "x-unknown"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{name: "preflight-not-routed-method", method: "OPTIONS", path: "/emoji/suggest",
+			header:     map[string]string{"Origin": "https://picker.example.com", "Access-Control-Request-Method": "DELETE"},
+			wantStatus: http.StatusMethodNotAllowed,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{name: "preflight-not-in-openapi", method: "OPTIONS", path: "/metrics",
+			header:     map[string]string{"Origin": "https://picker.example.com", "Access-Control-Request-Method": "GET"},
+			wantStatus: http.StatusMethodNotAllowed,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{name: "actual", method: "POST", path: "/emoji/suggest",
+			header:     map[string]string{"Origin": "https://picker.example.com", "Content-Type": "application/json"},
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "https://picker.example.com", "Access-Control-Expose-Headers": "X-Request-ID, Retry-After, ETag", "Vary": "Origin"},
+		},
+		{name: "actual-disallowed-origin", method: "POST", path: "/emoji/suggest",
+			header:     map[string]string{"Origin": "https://example.net", "Content-Type": "application/json"},
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{name: "same-origin", method: "POST", path: "/emoji/suggest",
+			header:     map[string]string{"Content-Type": "application/json"},
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"prefix": ":diz"}`))
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			got := map[string]string{}
+			for k := range tt.wantHeader {
+				got[k] = res.Header.Get(k)
+			}
+			if diff := cmp.Diff(tt.wantHeader, got); diff != "" {
+				t.Errorf("header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package server_test
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/emojilib"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+func TestEncoding(t *testing.T) {
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{Metrics: metrics.NewRegistry()})
+	size := len(emojilib.List()) // prefix ":" returns the entire catalog
+
+	tests := []struct {
+		name            string
+		accept          string
+		acceptEncoding  string
+		wantContentType string
+		wantEncoding    string
+		wantLines       int // the number of the lines of the decoded body (NDJSON and CSV)
+	}{
+		{name: "json", wantContentType: "application/json"},
+		{name: "json-gzip", acceptEncoding: "gzip", wantContentType: "application/json", wantEncoding: "gzip"},
+		{name: "json-deflate", acceptEncoding: "deflate", wantContentType: "application/json", wantEncoding: "deflate"},
+		{name: "unacceptable-is-json", accept: "text/html", wantContentType: "application/json"},
+		{name: "ndjson", accept: "application/x-ndjson", acceptEncoding: "gzip", wantContentType: "application/x-ndjson", wantEncoding: "gzip", wantLines: size},
+		{name: "csv", accept: "text/csv", wantContentType: "text/csv; charset=utf-8", wantLines: size + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/emoji/suggest", strings.NewReader(`{"prefix": ":"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := http.StatusOK, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			if want, got := tt.wantContentType, res.Header.Get("Content-Type"); want != got {
+				t.Errorf("content-type: want=%q, but got=%q", want, got)
+			}
+			if want, got := tt.wantEncoding, res.Header.Get("Content-Encoding"); want != got {
+				t.Errorf("content-encoding: want=%q, but got=%q", want, got)
+			}
+
+			var body io.Reader = res.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				r, err := gzip.NewReader(res.Body)
+				if err != nil {
+					t.Fatalf("unexpected error: %+v", err)
+				}
+				body = r
+			case "deflate":
+				body = flate.NewReader(res.Body)
+			}
+
+			if tt.wantLines == 0 {
+				var got []map[string]string
+				if err := json.NewDecoder(body).Decode(&got); err != nil {
+					t.Fatalf("unexpected error: %+v", err)
+				}
+				if want := size; len(got) != want {
+					t.Errorf("the number of the definitions: want=%d, but got=%d", want, len(got))
+				}
+				return
+			}
+
+			lines := 0
+			scanner := bufio.NewScanner(body)
+			for scanner.Scan() {
+				if lines == 0 && tt.accept == "text/csv" && scanner.Text() != "alias,char" {
+					t.Errorf("csv header: want=%q, but got=%q", "alias,char", scanner.Text())
+				}
+				lines++
+			}
+			if err := scanner.Err(); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if want, got := tt.wantLines, lines; want != got {
+				t.Errorf("the number of the lines: want=%d, but got=%d", want, got)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{Metrics: metrics.NewRegistry()})
+	hash := emojilib.CatalogHash()
+
+	tests := []struct {
+		name            string
+		path            string
+		accept          string
+		ifNoneMatch     string
+		wantStatus      int
+		wantContentType string
+		wantETag        string
+		wantVary        bool // Vary: Accept, if the format is selected by the Accept header
+	}{
+		{name: "default", path: "/emoji/export", wantStatus: http.StatusOK, wantContentType: "application/json", wantETag: `W/"` + hash + `-json"`, wantVary: true},
+		{name: "accept-csv", path: "/emoji/export", accept: "text/csv", wantStatus: http.StatusOK, wantContentType: "text/csv; charset=utf-8", wantETag: `W/"` + hash + `-csv"`, wantVary: true},
+		{name: "format-wins", path: "/emoji/export?format=ndjson", accept: "text/csv", wantStatus: http.StatusOK, wantContentType: "application/x-ndjson", wantETag: `W/"` + hash + `-ndjson"`},
+		{name: "not-modified", path: "/emoji/export", accept: "text/csv", ifNoneMatch: `W/"` + hash + `-csv"`, wantStatus: http.StatusNotModified, wantETag: `W/"` + hash + `-csv"`, wantVary: true},
+		{name: "list-not-modified", path: "/emoji?limit=10", ifNoneMatch: `W/"` + hash + `"`, wantStatus: http.StatusNotModified, wantETag: `W/"` + hash + `"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			if want, got := tt.wantETag, res.Header.Get("ETag"); want != got {
+				t.Errorf("etag: want=%q, but got=%q", want, got)
+			}
+			if want, got := tt.wantContentType, res.Header.Get("Content-Type"); tt.wantStatus == http.StatusOK && want != got {
+				t.Errorf("content-type: want=%q, but got=%q", want, got)
+			}
+			vary := false
+			for _, v := range strings.Split(strings.Join(res.Header.Values("Vary"), ","), ",") {
+				vary = vary || strings.TrimSpace(v) == "Accept"
+			}
+			if want, got := tt.wantVary, vary; want != got {
+				t.Errorf("vary accept: want=%v, but got=%v (%q)", want, got, res.Header.Values("Vary"))
+			}
+			if tt.wantStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("unexpected body: %q", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/middleware"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+func TestLimits(t *testing.T) {
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{
+		Metrics: metrics.NewRegistry(),
+		Limits:  middleware.LimitsConfig{MaxBodyBytes: 64, MaxTextLength: 20, MaxAliasTokens: 2},
+	})
+
+	tests := []struct {
+		name        string
+		body        string
+		chunked     bool // without Content-Length
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "ok", body: `{"text": "hmm :dizzy: :dizzy:"}`, wantStatus: http.StatusOK},
+		{name: "ok-multibyte", body: `{"text": "💫💫💫💫💫💫💫💫💫💫"}`, wantStatus: http.StatusOK}, // 10 characters
+		{name: "body-too-large", body: `{"text": "` + strings.Repeat("x", 100) + `"}`, wantStatus: http.StatusRequestEntityTooLarge, wantMessage: "request body is too large (max 64 bytes)"},
+		{name: "body-too-large-chunked", body: `{"text": "` + strings.Repeat("x", 100) + `"}`, chunked: true, wantStatus: http.StatusRequestEntityTooLarge, wantMessage: "request body is too large (max 64 bytes)"},
+		{name: "text-too-long", body: `{"text": "` + strings.Repeat("x", 21) + `"}`, wantStatus: http.StatusUnprocessableEntity, wantMessage: "text is too long: 21 characters (max 20)"},
+		{name: "too-many-aliases", body: `{"text": ":a: :b: :c:"}`, wantStatus: http.StatusUnprocessableEntity, wantMessage: "too many :<alias>: tokens: 3 (max 2)"},
+		{name: "invalid-json", body: `{"text": `, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.chunked {
+				body = io.MultiReader(body) // hide the length
+			}
+			req := httptest.NewRequest("POST", "/emoji/translate", body)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			if res.StatusCode == http.StatusOK {
+				return
+			}
+
+			if want, got := "application/json", res.Header.Get("Content-Type"); want != got {
+				t.Errorf("content-type: want=%q, but got=%q", want, got)
+			}
+			var got struct {
+				Message string `json:"message"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if tt.wantMessage != "" && got.Message != tt.wantMessage {
+				t.Errorf("message: want=%q, but got=%q", tt.wantMessage, got.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/middleware"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+func TestLogging(t *testing.T) {
+	cases := []struct {
+		name      string
+		requestID string // X-Request-ID of the request
+		wantID    string // "" means generated
+	}{
+		{name: "incoming", requestID: "req-123", wantID: "req-123"},
+		{name: "generated"},
+		{name: "invalid", requestID: "<script>"},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{Metrics: metrics.NewRegistry(), Logger: logger})
+
+			req := httptest.NewRequest("POST", "/emoji/translate", strings.NewReader(`{"text": "secret :dizzy:"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if c.requestID != "" {
+				req.Header.Set(middleware.RequestIDHeader, c.requestID)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if want, got := http.StatusOK, rec.Code; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+
+			id := rec.Header().Get(middleware.RequestIDHeader)
+			if c.wantID != "" && id != c.wantID {
+				t.Errorf("X-Request-ID: want=%q, but got=%q", c.wantID, id)
+			}
+			if id == "" || id == c.requestID && c.wantID == "" {
+				t.Errorf("X-Request-ID: want generated, but got=%q", id)
+			}
+
+			if strings.Contains(buf.String(), "secret") {
+				t.Errorf("the request text is logged\n%s", buf.String())
+			}
+
+			var access map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var record map[string]any
+				if err := json.Unmarshal([]byte(line), &record); err != nil {
+					t.Fatalf("unexpected log line: %+v\n%s", err, line)
+				}
+				if record["request_id"] != id {
+					t.Errorf("request_id: want=%q, but got=%v (msg=%v)", id, record["request_id"], record["msg"])
+				}
+				if record["msg"] == "request" {
+					access = record
+				}
+			}
+			if access == nil {
+				t.Fatalf("access log is not found\n%s", buf.String())
+			}
+			for k, want := range map[string]any{"method": "POST", "path": "/emoji/translate", "operation": "Translate", "status": float64(200)} {
+				if got := access[k]; want != got {
+					t.Errorf("access log %s: want=%v, but got=%v", k, want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/metrics"
+	"github.com/podhmo/emoji-api/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	registry := metrics.NewRegistry()
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{
+		Metrics:    registry,
+		RateLimits: map[string]ratelimit.Limit{"Suggest": {Rate: 0.5, Burst: 2}}, // 1 token per 2s
+	})
+
+	type request struct {
+		path       string
+		remoteAddr string
+		apiKey     string
+		wantStatus int
+	}
+	for i, x := range []request{
+		{path: "/emoji/suggest", remoteAddr: "192.0.2.1:1234", wantStatus: http.StatusOK},
+		{path: "/emoji/suggest", remoteAddr: "192.0.2.1:1235", wantStatus: http.StatusOK},
+		{path: "/emoji/suggest", remoteAddr: "192.0.2.1:1236", wantStatus: http.StatusTooManyRequests},
+		{path: "/emoji/suggest", remoteAddr: "192.0.2.2:1234", wantStatus: http.StatusOK},                             // the other client
+		{path: "/emoji/suggest", remoteAddr: "192.0.2.1:1237", apiKey: "xxx", wantStatus: http.StatusTooManyRequests}, // the key is not authenticated, keyed by the IP
+		{path: "/emoji/suggest", remoteAddr: "192.0.2.1:1238", apiKey: "yyy", wantStatus: http.StatusTooManyRequests},
+		{path: "/emoji/translate", remoteAddr: "192.0.2.1:1239", wantStatus: http.StatusOK}, // not limited
+	} {
+		body := `{"prefix": ":diz"}`
+		if x.path == "/emoji/translate" {
+			body = `{"text": ":dizzy:"}`
+		}
+		req := httptest.NewRequest("POST", x.path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = x.remoteAddr
+		if x.apiKey != "" {
+			req.Header.Set("X-API-Key", x.apiKey)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		res := rec.Result()
+
+		if want, got := x.wantStatus, res.StatusCode; want != got {
+			t.Fatalf("%d: status code: want=%d, but got=%d", i, want, got)
+		}
+		if res.StatusCode != http.StatusTooManyRequests {
+			continue
+		}
+
+		if want, got := "2", res.Header.Get("Retry-After"); want != got {
+			t.Errorf("%d: Retry-After: want=%q, but got=%q", i, want, got)
+		}
+		if want, got := "application/json", res.Header.Get("Content-Type"); want != got {
+			t.Errorf("%d: content-type: want=%q, but got=%q", i, want, got)
+		}
+		var got struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+			t.Fatalf("%d: unexpected error: %+v", i, err)
+		}
+		if got.Message == "" {
+			t.Errorf("%d: message should not be empty", i)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	b, _ := io.ReadAll(rec.Result().Body)
+	if line := `emoji_api_requests_total{operation="Suggest",code="429"} 3`; !strings.Contains(string(b), line+"\n") {
+		t.Errorf("want %q in /metrics, but not found\n%s", line, b)
+	}
+}
+
+func TestRateLimitAuthenticated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	keys := `
+keys:
+  - name: a
+    key: a-key
+    scopes: [read]
+  - name: b
+    key: b-key
+    scopes: [read]
+`
+	if err := os.WriteFile(path, []byte(keys), 0600); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	store, err := auth.LoadFileKeyStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{
+		Metrics:    metrics.NewRegistry(),
+		KeyStore:   store,
+		RateLimits: map[string]ratelimit.Limit{"Suggest": {Rate: 0.5, Burst: 1}},
+	})
+
+	for i, x := range []struct {
+		apiKey     string
+		wantStatus int
+	}{
+		{apiKey: "a-key", wantStatus: http.StatusOK},
+		{apiKey: "a-key", wantStatus: http.StatusTooManyRequests},
+		{apiKey: "b-key", wantStatus: http.StatusOK}, // the other key from the same IP has its own bucket
+	} {
+		req := httptest.NewRequest("POST", "/emoji/suggest", bytes.NewBufferString(`{"prefix": ":diz"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", x.apiKey)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if want, got := x.wantStatus, rec.Result().StatusCode; want != got {
+			t.Fatalf("%d: status code: want=%d, but got=%d", i, want, got)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package server assembles the HTTP handler of the emoji API (the routes of oapigen, and the others like /metrics).
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/podhmo/emoji-api/api/encoding"
+	"github.com/podhmo/emoji-api/api/middleware"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/metrics"
+	"github.com/podhmo/emoji-api/ratelimit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Config struct {
+	Metrics        *metrics.Registry    // default: metrics.Default (including the metrics of emojilib)
+	TracerProvider trace.TracerProvider // default: otel.GetTracerProvider() (the spans in the controllers and emojilib use the global provider)
+	Logger         *slog.Logger         // default: slog.Default()
+
+	RateLimits     map[string]ratelimit.Limit // the limit for each operation (e.g. "Suggest"), not limited if nil
+	RateLimitStore ratelimit.Store            // default: ratelimit.NewMemoryStore()
+
+	Limits middleware.LimitsConfig // the size of the request body, the text length and the alias tokens of translate (default if zero)
+
+	KeyStore auth.KeyStore // the API keys of the operations having the security requirements, the auth is disabled if nil
+
+	CORS middleware.CORSConfig // the preflight requests are answered for the operations, disabled if no origins
+}
+
+// CompressibleContentTypes is the content types of the responses compressed with gzip or deflate.
+var CompressibleContentTypes = []string{encoding.JSON, encoding.NDJSON, encoding.CSV, "text/plain"}
+
+// NewHandler returns the handler serving the operations of ssi, and GET /metrics.
+func NewHandler(ssi oapigen.StrictServerInterface, config Config) http.Handler {
+	if config.Metrics == nil {
+		config.Metrics = metrics.Default
+	}
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	apiRouter := chi.NewRouter() // the operations of openapi.json
+
+	router := chi.NewRouter()
+	router.Use(middleware.TraceHTTP(config.TracerProvider)) // outside Logging(), the access log has trace_id
+	router.Use(middleware.Logging(config.Logger))
+	router.Use(middleware.CORS(config.CORS, apiRouter))
+	router.Use(chimiddleware.Compress(5, CompressibleContentTypes...)) // gzip or deflate, by Accept-Encoding
+	router.Method(http.MethodGet, "/metrics", config.Metrics.Handler())
+	router.Mount("/", apiRouter)
+
+	middlewares := []oapigen.StrictMiddlewareFunc{
+		middleware.Negotiate(),
+		middleware.Limits(config.Limits),
+		middleware.RateLimit(middleware.RateLimitConfig{Limits: config.RateLimits, Store: config.RateLimitStore}),
+		middleware.LogOperation(),
+		middleware.Tracing(), // the last one is the outermost
+	}
+	httpMiddlewares := []oapigen.MiddlewareFunc{
+		middleware.MaxBytes(config.Limits),
+		middleware.TraceDecode(config.TracerProvider),
+	}
+	if config.KeyStore != nil {
+		httpMiddlewares = append(httpMiddlewares, middleware.Auth(config.KeyStore))
+	}
+	httpMiddlewares = append(httpMiddlewares, middleware.Metrics(config.Metrics)) // counts the responses of the middlewares above, too
+
+	options := oapigen.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  middleware.WriteRequestError,
+		ResponseErrorHandlerFunc: middleware.WriteError,
+	}
+	oapigen.HandlerWithOptions(oapigen.NewStrictHandlerWithOptions(ssi, middlewares, options), oapigen.ChiServerOptions{
+		BaseRouter:  apiRouter,
+		Middlewares: httpMiddlewares, // the last one is the outermost
+	})
+	return router
+}
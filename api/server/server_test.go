@@ -0,0 +1,108 @@
+package server_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/middleware"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{Metrics: metrics.NewRegistry()})
+
+	for _, body := range []string{`{"prefix": ":diz"}`, `{"prefix": ":smil", "limit": 1}`} {
+		req := httptest.NewRequest("POST", "/emoji/suggest", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if want, got := http.StatusOK, rec.Code; want != got {
+			t.Fatalf("status code: want=%d, but got=%d", want, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	res := rec.Result()
+	if want, got := http.StatusOK, res.StatusCode; want != got {
+		t.Fatalf("status code: want=%d, but got=%d", want, got)
+	}
+	if want, got := "text/plain; version=0.0.4; charset=utf-8", res.Header.Get("Content-Type"); want != got {
+		t.Errorf("content-type: want=%q, but got=%q", want, got)
+	}
+
+	b, _ := io.ReadAll(res.Body)
+	for _, line := range []string{
+		`emoji_api_requests_total{operation="Suggest",code="200"} 2`,
+		`emoji_api_request_duration_seconds_count{operation="Suggest"} 2`,
+		`# TYPE emoji_api_errors_total counter`,
+	} {
+		if !strings.Contains(string(b), line+"\n") {
+			t.Errorf("want %q in /metrics, but not found\n%s", line, b)
+		}
+	}
+}
+
+// TestMetricsOfRejectedRequests counts the responses written before the operation (e.g. 401 by Auth, 413 by MaxBytes).
+func TestMetricsOfRejectedRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	if err := os.WriteFile(path, []byte("keys:\n  - name: reader\n    key: read-key\n    scopes: [read]\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	store, err := auth.LoadFileKeyStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{
+		Metrics:  metrics.NewRegistry(),
+		Limits:   middleware.LimitsConfig{MaxBodyBytes: 64},
+		KeyStore: store,
+	})
+
+	for _, tt := range []struct {
+		key        string
+		body       string
+		wantStatus int
+	}{
+		{key: "", body: `{"text": ":dizzy:"}`, wantStatus: http.StatusUnauthorized},
+		{key: "read-key", body: `{"text": "` + strings.Repeat("x", 100) + `"}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{key: "read-key", body: `{"text": `, wantStatus: http.StatusBadRequest},
+		{key: "read-key", body: `{"text": ":dizzy:"}`, wantStatus: http.StatusOK},
+	} {
+		req := httptest.NewRequest("POST", "/emoji/translate", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if want, got := tt.wantStatus, rec.Code; want != got {
+			t.Fatalf("status code: want=%d, but got=%d", want, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	b, _ := io.ReadAll(rec.Result().Body)
+	for _, line := range []string{
+		`emoji_api_requests_total{operation="Translate",code="200"} 1`,
+		`emoji_api_requests_total{operation="Translate",code="400"} 1`,
+		`emoji_api_requests_total{operation="Translate",code="401"} 1`,
+		`emoji_api_requests_total{operation="Translate",code="413"} 1`,
+		`emoji_api_errors_total{operation="Translate"} 3`,
+		`emoji_api_request_duration_seconds_count{operation="Translate"} 4`,
+	} {
+		if !strings.Contains(string(b), line+"\n") {
+			t.Errorf("want %q in /metrics, but not found\n%s", line, b)
+		}
+	}
+}
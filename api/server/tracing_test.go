@@ -0,0 +1,128 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp) // the controllers and emojilib use the global provider
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{Metrics: metrics.NewRegistry(), TracerProvider: tp, Logger: logger})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("POST", "/emoji/suggest", bytes.NewBufferString(`{"prefix": ":diz"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if want, got := http.StatusOK, rec.Code; want != got {
+		t.Fatalf("status code: want=%d, but got=%d", want, got)
+	}
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, s := range spans {
+		byName[s.Name] = s
+		if want, got := traceID, s.SpanContext.TraceID().String(); want != got {
+			t.Errorf("trace id of %s: want=%s, but got=%s", s.Name, want, got)
+		}
+	}
+
+	// child -> parent
+	want := map[string]string{
+		"strictHandler.decode":    "POST /emoji/suggest",
+		"EmojiController.Suggest": "POST /emoji/suggest",
+		"emojilib.Suggest":        "EmojiController.Suggest",
+	}
+	got := map[string]string{}
+	for child := range want {
+		s, ok := byName[child]
+		if !ok {
+			t.Fatalf("span %q is not found in %d spans", child, len(spans))
+		}
+		for name, parent := range byName {
+			if parent.SpanContext.SpanID() == s.Parent.SpanID() {
+				got[child] = name
+			}
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parent of spans, mismatch (-want +got):\n%s", diff)
+	}
+
+	decode := byName["strictHandler.decode"]
+	if want, got := codes.Unset, decode.Status.Code; want != got {
+		t.Errorf("status of strictHandler.decode: want=%v, but got=%v", want, got)
+	}
+	if end, start := decode.EndTime, byName["EmojiController.Suggest"].StartTime; end.After(start) {
+		t.Errorf("strictHandler.decode should end before EmojiController.Suggest starts: end=%v, start=%v", end, start)
+	}
+
+	var access map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &access); err != nil {
+		t.Fatalf("unexpected access log: %+v\n%s", err, logs.String())
+	}
+	if want, got := traceID, access["trace_id"]; want != got {
+		t.Errorf("trace_id of the access log: want=%v, but got=%v", want, got)
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range byName["emojilib.Suggest"].Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if want, got := int64(4), attrs["emoji.prefix_length"].AsInt64(); want != got {
+		t.Errorf("emoji.prefix_length: want=%d, but got=%d", want, got)
+	}
+	if want, got := int64(2), attrs["emoji.result_count"].AsInt64(); want != got {
+		t.Errorf("emoji.result_count: want=%d, but got=%d", want, got)
+	}
+}
+
+func TestTracingDecodeError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	h := server.NewHandler(api.NewApiController(api.Dependencies{}), server.Config{Metrics: metrics.NewRegistry(), TracerProvider: tp})
+
+	req := httptest.NewRequest("POST", "/emoji/suggest", bytes.NewBufferString(`{"prefix": `))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if want, got := http.StatusBadRequest, rec.Code; want != got {
+		t.Fatalf("status code: want=%d, but got=%d", want, got)
+	}
+
+	var decode []tracetest.SpanStub
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "strictHandler.decode" {
+			decode = append(decode, s)
+		}
+	}
+	if want, got := 1, len(decode); want != got {
+		t.Fatalf("the number of strictHandler.decode spans: want=%d, but got=%d", want, got)
+	}
+	if want, got := codes.Error, decode[0].Status.Code; want != got {
+		t.Errorf("status of strictHandler.decode: want=%v, but got=%v", want, got)
+	}
+}
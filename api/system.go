@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/emojilib"
+)
+
+type SystemController struct {
+	Ready func() bool // default: emojilib.Ready (the catalog has been built)
+}
+
+func NewSystemController() *SystemController {
+	return &SystemController{}
+}
+
+// Healthz is endpoint of GET /healthz
+// liveness probe, always ok while the server is running
+//
+// * 200    :oapigen.Healthz200JSONResponse      -- "the status of the server"
+// * default:oapigen.HealthzdefaultJSONResponse  -- "default error"
+func (c *SystemController) Healthz(ctx context.Context, request oapigen.HealthzRequestObject) (response oapigen.HealthzResponseObject, err error) {
+	response = oapigen.Healthz200JSONResponse{Status: "ok"}
+	return
+}
+
+// Readyz is endpoint of GET /readyz
+// readiness probe, 503 until the emoji catalog has been built
+//
+// * 200    :oapigen.Readyz200JSONResponse       -- "the status of the server"
+// * default:oapigen.ReadyzdefaultJSONResponse   -- "default error"
+func (c *SystemController) Readyz(ctx context.Context, request oapigen.ReadyzRequestObject) (response oapigen.ReadyzResponseObject, err error) {
+	ready := c.Ready
+	if ready == nil {
+		ready = emojilib.Ready
+	}
+	if !ready() {
+		response = oapigen.ReadyzdefaultJSONResponse{StatusCode: http.StatusServiceUnavailable, Body: oapigen.Error{Message: "the emoji catalog is not built yet"}}
+		return
+	}
+	response = oapigen.Readyz200JSONResponse{Status: "ok"}
+	return
+}
+
+// Version is endpoint of GET /version
+// the module version, VCS revision and the emoji catalog size/version
+//
+// * 200    :oapigen.Version200JSONResponse      -- "the build information of the server"
+// * default:oapigen.VersiondefaultJSONResponse  -- "default error"
+func (c *SystemController) Version(ctx context.Context, request oapigen.VersionRequestObject) (response oapigen.VersionResponseObject, err error) {
+	got := oapigen.BuildInfo{
+		Version:        "unknown",
+		CatalogSize:    emojilib.CatalogSize(),
+		CatalogVersion: emojilib.CatalogVersion(),
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Version != "" { // empty in the test binaries
+			got.Version = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				revision := s.Value
+				got.Revision = &revision
+			}
+		}
+	}
+	response = oapigen.Version200JSONResponse(got)
+	return
+}
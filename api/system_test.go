@@ -0,0 +1,107 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/api"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/emojilib"
+)
+
+func newSystemController() oapigen.StrictServerInterface {
+	return &api.ApiController{SystemController: api.NewSystemController()}
+}
+
+func TestSystemHealthz(t *testing.T) {
+	h := newHandler(newSystemController())
+
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := rec.Result()
+
+	if want, got := http.StatusOK, res.StatusCode; want != got {
+		t.Fatalf("status code: want=%d, but got=%d", want, got)
+	}
+
+	var got oapigen.Health
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Errorf("unexpected error (json.Unmarshal): %+v", err)
+	}
+	defer res.Body.Close()
+
+	want := oapigen.Health{Status: "ok"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("response body, mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSystemReadyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		ready      bool
+		wantStatus int
+	}{
+		{name: "ready", ready: true, wantStatus: http.StatusOK},
+		{name: "catalog-not-built", ready: false, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready := tt.ready
+			h := newHandler(&api.ApiController{SystemController: &api.SystemController{Ready: func() bool { return ready }}})
+			req, _ := http.NewRequest("GET", "/readyz", nil)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		emojilib.Warmup()
+		h := newHandler(newSystemController())
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if want, got := http.StatusOK, rec.Code; want != got {
+			t.Fatalf("status code: want=%d, but got=%d", want, got)
+		}
+	})
+}
+
+func TestSystemVersion(t *testing.T) {
+	h := newHandler(newSystemController())
+
+	req, _ := http.NewRequest("GET", "/version", nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := rec.Result()
+
+	if want, got := http.StatusOK, res.StatusCode; want != got {
+		t.Fatalf("status code: want=%d, but got=%d", want, got)
+	}
+
+	var got oapigen.BuildInfo
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Errorf("unexpected error (json.Unmarshal): %+v", err)
+	}
+	defer res.Body.Close()
+
+	if want := len(emojilib.List()); got.CatalogSize != want {
+		t.Errorf("catalogSize: want=%d, but got=%d", want, got.CatalogSize)
+	}
+	if got.Version == "" || got.CatalogVersion == "" {
+		t.Errorf("version and catalogVersion should not be empty: %+v", got)
+	}
+}
@@ -0,0 +1,138 @@
+// Package auth is the API keys with the scopes (e.g. read, write), and the file-backed key store.
+//
+//	keys:
+//	  - name: local
+//	    key: dev-key          # the raw key (for development)
+//	    scopes: [read]
+//	  - name: admin
+//	    sha256: 5e884898...   # the hex encoded sha256 of the key
+//	    scopes: [read, write]
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownKey is returned by KeyStore.Lookup() if the key is not found.
+var ErrUnknownKey = errors.New("unknown API key")
+
+// Key is the API key entry, the raw key is not kept after loading.
+type Key struct {
+	Name   string   `yaml:"name"`
+	Key    string   `yaml:"key,omitempty"`
+	SHA256 string   `yaml:"sha256,omitempty"`
+	Scopes []string `yaml:"scopes"`
+}
+
+// HasScopes reports whether the key has all of the scopes.
+func (k *Key) HasScopes(scopes ...string) bool {
+	for _, want := range scopes {
+		found := false
+		for _, s := range k.Scopes {
+			if s == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyStore looks up the API key.
+type KeyStore interface {
+	Lookup(ctx context.Context, key string) (*Key, error)
+}
+
+// FileKeyStore is the KeyStore loaded from the YAML file (see the package doc), reloaded by Reload().
+type FileKeyStore struct {
+	path string
+
+	mu   sync.RWMutex
+	keys map[[sha256.Size]byte]*Key
+}
+
+func LoadFileKeyStore(path string) (*FileKeyStore, error) {
+	s := &FileKeyStore{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the file again, the current keys are kept if failed.
+func (s *FileKeyStore) Reload() error {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("read key store: %w", err)
+	}
+	var file struct {
+		Keys []*Key `yaml:"keys"`
+	}
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return fmt.Errorf("load key store %s: %w", s.path, err)
+	}
+
+	keys := make(map[[sha256.Size]byte]*Key, len(file.Keys))
+	for i, k := range file.Keys {
+		var sum [sha256.Size]byte
+		switch {
+		case k.Name == "":
+			return fmt.Errorf("load key store %s: keys[%d]: name is required", s.path, i)
+		case (k.Key == "") == (k.SHA256 == ""):
+			return fmt.Errorf("load key store %s: keys[%d] (%s): one of key or sha256 is required", s.path, i, k.Name)
+		case k.Key != "":
+			sum = sha256.Sum256([]byte(k.Key))
+		default:
+			decoded, err := hex.DecodeString(k.SHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return fmt.Errorf("load key store %s: keys[%d] (%s): sha256 should be the hex encoded sha256 digest", s.path, i, k.Name)
+			}
+			copy(sum[:], decoded)
+		}
+		if _, dup := keys[sum]; dup {
+			return fmt.Errorf("load key store %s: keys[%d] (%s): duplicated key", s.path, i, k.Name)
+		}
+		keys[sum] = &Key{Name: k.Name, SHA256: hex.EncodeToString(sum[:]), Scopes: k.Scopes}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	return nil
+}
+
+// Lookup returns the entry of the key, or ErrUnknownKey.
+func (s *FileKeyStore) Lookup(ctx context.Context, key string) (*Key, error) {
+	sum := sha256.Sum256([]byte(key))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if k, ok := s.keys[sum]; ok {
+		return k, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+type keyContextKey struct{}
+
+// WithKey returns the context having the authenticated key.
+func WithKey(ctx context.Context, k *Key) context.Context {
+	return context.WithValue(ctx, keyContextKey{}, k)
+}
+
+// KeyFromContext returns the authenticated key, set by the auth middleware.
+func KeyFromContext(ctx context.Context) (*Key, bool) {
+	k, ok := ctx.Value(keyContextKey{}).(*Key)
+	return k, ok
+}
@@ -0,0 +1,122 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/auth"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	return path
+}
+
+func TestFileKeyStore(t *testing.T) {
+	// sha256("secret") = 2bb80d53...
+	path := writeFile(t, `
+keys:
+  - name: local
+    key: dev-key
+    scopes: [read]
+  - name: admin
+    sha256: 2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b
+    scopes: [read, write]
+`)
+	store, err := auth.LoadFileKeyStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	tests := []struct {
+		key        string
+		wantName   string
+		wantScopes []string
+		wantErr    error
+	}{
+		{key: "dev-key", wantName: "local", wantScopes: []string{"read"}},
+		{key: "secret", wantName: "admin", wantScopes: []string{"read", "write"}},
+		{key: "unknown", wantErr: auth.ErrUnknownKey},
+		{key: "", wantErr: auth.ErrUnknownKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := store.Lookup(context.Background(), tt.key)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("name: want=%q, but got=%q", tt.wantName, got.Name)
+			}
+			if diff := cmp.Diff(tt.wantScopes, got.Scopes); diff != "" {
+				t.Errorf("scopes mismatch (-want +got):\n%s", diff)
+			}
+			if got.Key != "" {
+				t.Errorf("the raw key should not be kept: %q", got.Key)
+			}
+		})
+	}
+
+	t.Run("reload", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("keys:\n  - name: new\n    key: new-key\n    scopes: [read]\n"), 0600); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if err := store.Reload(); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if _, err := store.Lookup(context.Background(), "new-key"); err != nil {
+			t.Errorf("new-key: unexpected error: %+v", err)
+		}
+		if _, err := store.Lookup(context.Background(), "dev-key"); !errors.Is(err, auth.ErrUnknownKey) {
+			t.Errorf("dev-key: want ErrUnknownKey, but got %v", err)
+		}
+	})
+}
+
+func TestLoadFileKeyStoreError(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "no-name", content: "keys:\n  - key: k\n"},
+		{name: "no-key", content: "keys:\n  - name: x\n"},
+		{name: "both", content: "keys:\n  - name: x\n    key: k\n    sha256: 2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b\n"},
+		{name: "invalid-sha256", content: "keys:\n  - name: x\n    sha256: xyz\n"},
+		{name: "duplicated", content: "keys:\n  - name: x\n    key: k\n  - name: y\n    key: k\n"},
+		{name: "invalid-yaml", content: "keys: ["},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := auth.LoadFileKeyStore(writeFile(t, tt.content)); err == nil {
+				t.Errorf("want error, but nil")
+			}
+		})
+	}
+}
+
+func TestKeyHasScopes(t *testing.T) {
+	k := &auth.Key{Name: "x", Scopes: []string{"read"}}
+	if !k.HasScopes("read") {
+		t.Errorf("HasScopes(read) should be true")
+	}
+	if k.HasScopes("read", "write") {
+		t.Errorf("HasScopes(read, write) should be false")
+	}
+	if !k.HasScopes() {
+		t.Errorf("HasScopes() should be true")
+	}
+}
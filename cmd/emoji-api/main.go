@@ -0,0 +1,143 @@
+// Command emoji-api is the server of the emoji API (HTTP and gRPC on one port).
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/podhmo/emoji-api/api"
+	"github.com/podhmo/emoji-api/api/middleware"
+	"github.com/podhmo/emoji-api/api/rpc"
+	"github.com/podhmo/emoji-api/api/server"
+	"github.com/podhmo/emoji-api/auth"
+	"github.com/podhmo/emoji-api/emojilib"
+	"github.com/podhmo/emoji-api/ratelimit"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type Options struct {
+	Addr      string
+	Trace     string            // the exporter of the spans (stdout or "")
+	LogFormat string            // text or json
+	LogLevel  string            // debug, info, warn, error
+	RateLimit map[string]string // operation -> limit (e.g. Suggest=20/s:40)
+	Limits    middleware.LimitsConfig
+	Keys      string // the key store file (auth is disabled if empty)
+	CORS      middleware.CORSConfig
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("** ")
+
+	options := &Options{}
+	pflag.StringVar(&options.Addr, "addr", ":8080", "listen address")
+	pflag.StringVar(&options.Trace, "trace", "", "export the spans (stdout), disabled if empty")
+	pflag.StringVar(&options.LogFormat, "log-format", "text", "log format (text|json)")
+	pflag.StringVar(&options.LogLevel, "log-level", "info", "log level (debug|info|warn|error), debug if DEBUG=1")
+	pflag.StringToStringVar(&options.RateLimit, "rate-limit", map[string]string{"Suggest": "20/s:40", "Translate": "5/s:10", "Export": "1/s:5"}, "the rate limit for each operation and client (<operation>=<n>/<s|m|h>[:<burst>])")
+	pflag.Int64Var(&options.Limits.MaxBodyBytes, "max-body-bytes", middleware.DefaultMaxBodyBytes, "the max size of the request body (413), unlimited if negative")
+	pflag.IntVar(&options.Limits.MaxTextLength, "max-text-length", middleware.DefaultMaxTextLength, "the max length of the text of translate (422), unlimited if negative")
+	pflag.IntVar(&options.Limits.MaxAliasTokens, "max-alias-tokens", middleware.DefaultMaxAliasTokens, "the max number of the :<alias>: tokens of translate (422), unlimited if negative")
+	pflag.StringVar(&options.Keys, "keys", "", "the API key file (YAML, reloaded on SIGHUP), the auth is disabled if empty")
+	pflag.StringSliceVar(&options.CORS.AllowedOrigins, "cors-origin", nil, "the allowed origins of CORS (e.g. https://example.com, https://*.example.com, *), disabled if empty")
+	pflag.StringSliceVar(&options.CORS.AllowedHeaders, "cors-header", middleware.DefaultCORSAllowedHeaders, "the allowed request headers of CORS")
+	pflag.DurationVar(&options.CORS.MaxAge, "cors-max-age", 10*time.Minute, "the max age of the preflight response")
+	pflag.Parse()
+
+	if err := run(options); err != nil {
+		log.Fatalf("!! %+v", err)
+	}
+}
+
+func run(options *Options) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(options.LogLevel)); err != nil {
+		return fmt.Errorf("unexpected --log-level value: %w", err)
+	}
+	if ok, _ := strconv.ParseBool(os.Getenv("DEBUG")); ok {
+		level = slog.LevelDebug
+	}
+	handlerOptions := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch options.LogFormat {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, handlerOptions)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, handlerOptions)
+	default:
+		return fmt.Errorf("unexpected --log-format value: %q (text|json)", options.LogFormat)
+	}
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
+	switch options.Trace {
+	case "":
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return fmt.Errorf("new exporter: %w", err)
+		}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+		defer tp.Shutdown(context.Background()) // nolint
+		otel.SetTracerProvider(tp)
+	default:
+		return fmt.Errorf("unexpected --trace value: %q (stdout)", options.Trace)
+	}
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ssi := api.NewApiController(api.Dependencies{})
+	rateLimits := make(map[string]ratelimit.Limit, len(options.RateLimit))
+	for operation, v := range options.RateLimit {
+		limit, err := ratelimit.ParseLimit(v)
+		if err != nil {
+			return fmt.Errorf("unexpected --rate-limit value for %s: %w", operation, err)
+		}
+		rateLimits[operation] = limit
+	}
+
+	rateLimitStore := ratelimit.NewMemoryStore() // shared by HTTP and gRPC
+	config := server.Config{Logger: logger, RateLimits: rateLimits, RateLimitStore: rateLimitStore, Limits: options.Limits, CORS: options.CORS}
+	rpcConfig := rpc.Config{Logger: logger, RateLimits: rateLimits, RateLimitStore: rateLimitStore, Limits: options.Limits}
+	if options.Keys == "" {
+		logger.Warn("the auth is disabled (no --keys)")
+	} else {
+		store, err := auth.LoadFileKeyStore(options.Keys)
+		if err != nil {
+			return fmt.Errorf("load --keys: %w", err)
+		}
+		go reloadOnSIGHUP(logger, store)
+		config.KeyStore = store
+		rpcConfig.KeyStore = store
+	}
+
+	h := rpc.Handler(rpc.NewServer(rpc.ServerOptions(rpcConfig)...), server.NewHandler(ssi, config))
+
+	go emojilib.Warmup() // /readyz returns 503 until the catalog is built
+	logger.Info("listening", "addr", options.Addr)
+	return http.ListenAndServe(options.Addr, h)
+}
+
+func reloadOnSIGHUP(logger *slog.Logger, store *auth.FileKeyStore) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	for range c {
+		if err := store.Reload(); err != nil {
+			logger.Error("reload the API keys", "error", err)
+			continue
+		}
+		logger.Info("reloaded the API keys")
+	}
+}
@@ -0,0 +1,214 @@
+// Command emoji is the offline version of the emoji API (without the server).
+//
+//	$ echo 'hmm :dizzy:' | emoji translate
+//	$ emoji translate --in-place README.md
+//	$ emoji suggest :smil --limit 3 --reverse
+//	$ emoji list
+//	$ emoji lookup :dizzy:
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/podhmo/emoji-api/api"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/emojilib"
+	"github.com/spf13/pflag"
+)
+
+const usage = `Usage: emoji <command> [options]
+
+Commands:
+  translate [--in-place] [FILE...]     translate :<alias>: in stdin (or files) to emoji
+  suggest PREFIX [--limit N] [--reverse] [--json]
+                                       list the emoji whose alias starts with PREFIX
+  list [--json]                        list all emoji
+  lookup ALIAS_OR_CHAR... [--json]     show the emoji matched with the alias (or the char)
+`
+
+type Command struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func main() {
+	cmd := &Command{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	if err := cmd.Run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "emoji: %v\n", err) // nolint
+		os.Exit(1)
+	}
+}
+
+// Run runs the sub command.
+func (c *Command) Run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(c.Stderr, usage) // nolint
+		return fmt.Errorf("command is required")
+	}
+	switch name, args := args[0], args[1:]; name {
+	case "translate":
+		return c.Translate(args)
+	case "suggest":
+		return c.Suggest(args)
+	case "list":
+		return c.List(args)
+	case "lookup":
+		return c.Lookup(args)
+	case "help", "-h", "--help":
+		fmt.Fprint(c.Stdout, usage) // nolint
+		return nil
+	default:
+		fmt.Fprint(c.Stderr, usage) // nolint
+		return fmt.Errorf("unknown command: %q", name)
+	}
+}
+
+func (c *Command) flagSet(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	fs.SetInterspersed(true)
+	return fs
+}
+
+// Translate translates stdin to stdout, or the files (to stdout, or in-place).
+func (c *Command) Translate(args []string) error {
+	fs := c.flagSet("translate")
+	inPlace := fs.BoolP("in-place", "i", false, "rewrite the files in place")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		if *inPlace {
+			return fmt.Errorf("translate: --in-place requires the files")
+		}
+		b, err := io.ReadAll(c.Stdin)
+		if err != nil {
+			return fmt.Errorf("translate: read stdin: %w", err)
+		}
+		_, err = io.WriteString(c.Stdout, emojilib.Translate(string(b)))
+		return err
+	}
+
+	for _, filename := range fs.Args() {
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("translate: %w", err)
+		}
+		translated := emojilib.Translate(string(b))
+		if !*inPlace {
+			if _, err := io.WriteString(c.Stdout, translated); err != nil {
+				return err
+			}
+			continue
+		}
+		if translated == string(b) {
+			continue
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			return fmt.Errorf("translate: %w", err)
+		}
+		if err := os.WriteFile(filename, []byte(translated), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("translate: %w", err)
+		}
+	}
+	return nil
+}
+
+// Suggest lists the emoji whose alias starts with the prefix. The options are same as POST /emoji/suggest.
+func (c *Command) Suggest(args []string) error {
+	fs := c.flagSet("suggest")
+	limit := fs.Int("limit", 0, "max number of suggestions (0 is unlimited)")
+	reverse := fs.Bool("reverse", false, "sort in descending order (same as sort=desc)")
+	asJSON := fs.Bool("json", false, "output as JSON (same as the response of POST /emoji/suggest)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("suggest: PREFIX is required")
+	}
+	if *limit < 0 {
+		return fmt.Errorf("suggest: --limit must not be negative: %d", *limit)
+	}
+
+	body := oapigen.SuggestJSONRequestBody{Prefix: fs.Arg(0), Sort: oapigen.SuggestJSONBodySortAsc}
+	if !strings.HasPrefix(body.Prefix, ":") {
+		body.Prefix = ":" + body.Prefix
+	}
+	if fs.Changed("limit") {
+		body.Limit = limit
+	}
+	if *reverse {
+		body.Sort = oapigen.SuggestJSONBodySortDesc
+	}
+	return c.write(emojilib.Suggest(body.Prefix, api.NewSuggestOption(body)), *asJSON)
+}
+
+// List lists all emoji.
+func (c *Command) List(args []string) error {
+	fs := c.flagSet("list")
+	asJSON := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("list: unexpected arguments: %v", fs.Args())
+	}
+	return c.write(emojilib.List(), *asJSON)
+}
+
+// Lookup shows the emoji matched with the aliases or the chars.
+func (c *Command) Lookup(args []string) error {
+	fs := c.flagSet("lookup")
+	asJSON := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("lookup: ALIAS_OR_CHAR is required")
+	}
+
+	var defs []emojilib.Definition
+	var notFound []string
+	for _, x := range fs.Args() {
+		found := emojilib.Lookup(x)
+		if len(found) == 0 {
+			notFound = append(notFound, x)
+		}
+		defs = append(defs, found...)
+	}
+	if err := c.write(defs, *asJSON); err != nil {
+		return err
+	}
+	if len(notFound) > 0 {
+		return fmt.Errorf("lookup: not found: %s", strings.Join(notFound, ", "))
+	}
+	return nil
+}
+
+// write writes the definitions as "<alias>\t<char>" lines, or as JSON ([]oapigen.EmojiDefinition).
+func (c *Command) write(defs []emojilib.Definition, asJSON bool) error {
+	if asJSON {
+		r := make([]oapigen.EmojiDefinition, len(defs))
+		for i, x := range defs {
+			r[i] = oapigen.EmojiDefinition{Alias: x.Alias, Char: x.Char}
+		}
+		enc := json.NewEncoder(c.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	buf := new(bytes.Buffer)
+	for _, x := range defs {
+		fmt.Fprintf(buf, "%s\t%s\n", x.Alias, x.Char) // nolint
+	}
+	_, err := c.Stdout.Write(buf.Bytes())
+	return err
+}
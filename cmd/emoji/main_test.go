@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/")
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantErr bool
+	}{
+		{name: "translate-stdin", args: []string{"translate"}, stdin: "hmm :dizzy:\n(o_0) :smile:\n"},
+		{name: "translate-files", args: []string{"translate", "testdata/input.md"}},
+		{name: "suggest", args: []string{"suggest", ":smil", "--limit", "3"}},
+		{name: "suggest-without-colon", args: []string{"suggest", "diz"}},
+		{name: "suggest-reverse", args: []string{"suggest", ":smil", "--limit", "3", "--reverse"}},
+		{name: "suggest-json", args: []string{"suggest", "--json", ":dizzy"}},
+		{name: "suggest-negative-limit", args: []string{"suggest", ":smil", "--limit", "-1"}, wantErr: true},
+		{name: "lookup", args: []string{"lookup", ":dizzy:", "smile", "💫"}},
+		{name: "lookup-not-found", args: []string{"lookup", ":dizzy:", ":no-such-emoji:"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := new(bytes.Buffer)
+			cmd := &Command{Stdin: strings.NewReader(tt.stdin), Stdout: stdout, Stderr: new(bytes.Buffer)}
+			err := cmd.Run(tt.args)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assertGolden(t, filepath.Join("testdata", tt.name+".golden"), stdout.String())
+		})
+	}
+}
+
+func TestTranslateInPlace(t *testing.T) {
+	src, err := os.ReadFile("testdata/input.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "input.md")
+	if err := os.WriteFile(filename, src, 0644); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	stdout := new(bytes.Buffer)
+	cmd := &Command{Stdin: strings.NewReader(""), Stdout: stdout, Stderr: new(bytes.Buffer)}
+	if err := cmd.Run([]string{"translate", "--in-place", filename}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("want empty stdout, but got %q", stdout.String())
+	}
+
+	got, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	assertGolden(t, "testdata/translate-files.golden", string(got))
+}
+
+func TestList(t *testing.T) {
+	stdout := new(bytes.Buffer)
+	cmd := &Command{Stdin: strings.NewReader(""), Stdout: stdout, Stderr: new(bytes.Buffer)}
+	if err := cmd.Run([]string{"list"}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if !strings.Contains(stdout.String(), ":dizzy:\t💫\n") {
+		t.Errorf("want %q in the output of list, but not found", ":dizzy:\t💫")
+	}
+}
+
+func assertGolden(t *testing.T, golden string, got string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatalf("update golden file: %+v", err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update): %+v", err)
+	}
+	if diff := cmp.Diff(string(want), got); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+}
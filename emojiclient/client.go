@@ -0,0 +1,158 @@
+// Package emojiclient is the client of the emoji API, wrapping the generated client (api/client).
+//
+//	c := emojiclient.New("http://localhost:8080")
+//	suggestions, err := c.Suggest(ctx, "smi", emojiclient.WithLimit(5))
+package emojiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/podhmo/emoji-api/api/client"
+)
+
+// EmojiDefinition is the pair of alias and char (e.g. ":dizzy:" and "💫").
+type EmojiDefinition = client.EmojiDefinition
+
+// Error is the error response of the API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("emoji API error: status=%d, message=%q", e.StatusCode, e.Message)
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    *time.Duration // set by WithTimeout, applied after all options (then WithHTTPClient doesn't discard it)
+	retry      retryConfig
+	apiKey     string
+}
+
+// Option is the option for New.
+type Option func(*Client)
+
+// WithHTTPClient sets the http client (the timeout of the client is overwritten by WithTimeout, regardless of the order).
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		clone := *httpClient
+		c.httpClient = &clone
+	}
+}
+
+// WithTimeout sets the timeout of each request (default: 10s). The retries are not counted in.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = &timeout
+	}
+}
+
+// WithRetry sets the max number of retries and the initial interval (doubled for each retry).
+// The requests are retried on the network errors, 429 and 5xx (default: 2 retries, 100ms).
+// If the response has Retry-After, the client waits for it at least.
+func WithRetry(maxRetries int, interval time.Duration) Option {
+	return func(c *Client) {
+		c.retry = retryConfig{maxRetries: maxRetries, interval: interval}
+	}
+}
+
+// WithAPIKey sends the API key as the bearer token (Authorization: Bearer <key>).
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// New returns the client of the emoji API (e.g. baseURL = "http://localhost:8080").
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		retry:      retryConfig{maxRetries: 2, interval: 100 * time.Millisecond},
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	if c.timeout != nil {
+		c.httpClient.Timeout = *c.timeout
+	}
+	return c
+}
+
+func (c *Client) client() (*client.ClientWithResponses, error) {
+	doer := &retryDoer{doer: c.httpClient, config: c.retry}
+	options := []client.ClientOption{client.WithHTTPClient(doer)}
+	if c.apiKey != "" {
+		options = append(options, client.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+			return nil
+		}))
+	}
+	return client.NewClientWithResponses(c.baseURL, options...)
+}
+
+// SuggestOption is the option for Suggest.
+type SuggestOption func(*client.SuggestJSONRequestBody)
+
+// WithLimit limits the number of suggestions.
+func WithLimit(limit int) SuggestOption {
+	return func(body *client.SuggestJSONRequestBody) {
+		body.Limit = &limit
+	}
+}
+
+// WithReverse returns the suggestions in descending order.
+func WithReverse() SuggestOption {
+	return func(body *client.SuggestJSONRequestBody) {
+		body.Sort = client.SuggestJSONBodySortDesc
+	}
+}
+
+// Suggest returns the emoji definitions whose alias starts with the prefix (POST /emoji/suggest).
+func (c *Client) Suggest(ctx context.Context, prefix string, options ...SuggestOption) ([]EmojiDefinition, error) {
+	body := client.SuggestJSONRequestBody{Prefix: prefix, Sort: client.SuggestJSONBodySortAsc}
+	for _, opt := range options {
+		opt(&body)
+	}
+
+	cl, err := c.client()
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+	res, err := cl.SuggestWithResponse(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("suggest: %w", err)
+	}
+	if res.JSON200 == nil {
+		return nil, newError(res.StatusCode(), res.JSONDefault, res.Body)
+	}
+	return *res.JSON200, nil
+}
+
+// Translate converts the aliases in the text (e.g. ":dizzy:") to the emoji (POST /emoji/translate).
+func (c *Client) Translate(ctx context.Context, text string) (string, error) {
+	cl, err := c.client()
+	if err != nil {
+		return "", fmt.Errorf("new client: %w", err)
+	}
+	res, err := cl.TranslateWithResponse(ctx, client.TranslateJSONRequestBody{Text: text})
+	if err != nil {
+		return "", fmt.Errorf("translate: %w", err)
+	}
+	if res.JSON200 == nil {
+		return "", newError(res.StatusCode(), res.JSONDefault, res.Body)
+	}
+	return *res.JSON200, nil
+}
+
+func newError(statusCode int, body *client.Error, raw []byte) *Error {
+	if body != nil && body.Message != "" {
+		return &Error{StatusCode: statusCode, Message: body.Message}
+	}
+	return &Error{StatusCode: statusCode, Message: string(raw)}
+}
@@ -0,0 +1,189 @@
+package emojiclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/api"
+	oapigen "github.com/podhmo/emoji-api/api/oapigen"
+	"github.com/podhmo/emoji-api/emojiclient"
+)
+
+func newServer(t *testing.T, middlewares ...func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	router := chi.NewRouter()
+	router.Use(middlewares...)
+	ssi := api.NewApiController(api.Dependencies{})
+	ts := httptest.NewServer(oapigen.HandlerFromMux(oapigen.NewStrictHandler(ssi, nil), router))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestSuggest(t *testing.T) {
+	ts := newServer(t)
+	c := emojiclient.New(ts.URL)
+
+	tests := []struct {
+		name    string
+		prefix  string
+		options []emojiclient.SuggestOption
+		want    []emojiclient.EmojiDefinition
+	}{
+		{name: "limit", prefix: ":smil", options: []emojiclient.SuggestOption{emojiclient.WithLimit(2)},
+			want: []emojiclient.EmojiDefinition{{Alias: ":smile:", Char: "😄"}, {Alias: ":smile_cat:", Char: "😸"}}},
+		{name: "reverse", prefix: ":smil", options: []emojiclient.SuggestOption{emojiclient.WithLimit(1), emojiclient.WithReverse()},
+			want: []emojiclient.EmojiDefinition{{Alias: ":smiling_imp:", Char: "😈"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Suggest(context.Background(), tt.prefix, tt.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Suggest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	ts := newServer(t)
+	c := emojiclient.New(ts.URL)
+
+	got, err := c.Translate(context.Background(), "hello :dizzy:")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if want := "hello 💫"; want != got {
+		t.Errorf("Translate() mismatch: want=%q, but got=%q", want, got)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	var got atomic.Value
+	ts := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got.Store(r.Header.Get("Authorization"))
+			next.ServeHTTP(w, r)
+		})
+	})
+	c := emojiclient.New(ts.URL, emojiclient.WithAPIKey("read-key"))
+
+	if _, err := c.Translate(context.Background(), "hello :dizzy:"); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if want, got := "Bearer read-key", got.Load(); want != got {
+		t.Errorf("Authorization: want=%q, but got=%q", want, got)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var count int32
+	failTwice := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&count, 1) <= 2 {
+				http.Error(w, `{"message": "unavailable"}`, http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	t.Run("recovered", func(t *testing.T) {
+		atomic.StoreInt32(&count, 0)
+		ts := newServer(t, failTwice)
+		c := emojiclient.New(ts.URL, emojiclient.WithRetry(2, time.Millisecond))
+
+		got, err := c.Translate(context.Background(), ":dizzy:")
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if want := "💫"; want != got {
+			t.Errorf("Translate() mismatch: want=%q, but got=%q", want, got)
+		}
+		if want, got := int32(3), atomic.LoadInt32(&count); want != got {
+			t.Errorf("the number of requests: want=%d, but got=%d", want, got)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		atomic.StoreInt32(&count, 0)
+		ts := newServer(t, failTwice)
+		c := emojiclient.New(ts.URL, emojiclient.WithRetry(1, time.Millisecond))
+
+		_, err := c.Translate(context.Background(), ":dizzy:")
+		var apiErr *emojiclient.Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("want *emojiclient.Error, but got %+v", err)
+		}
+		if want, got := http.StatusServiceUnavailable, apiErr.StatusCode; want != got {
+			t.Errorf("status code: want=%d, but got=%d", want, got)
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	var count int32
+	limited := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&count, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, `{"message": "rate limit exceeded"}`, http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	ts := newServer(t, limited)
+	c := emojiclient.New(ts.URL, emojiclient.WithRetry(1, time.Millisecond))
+
+	start := time.Now()
+	if _, err := c.Translate(context.Background(), ":dizzy:"); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("want to wait for Retry-After (1s) before the retry, but retried in %s", elapsed)
+	}
+	if want, got := int32(2), atomic.LoadInt32(&count); want != got {
+		t.Errorf("the number of requests: want=%d, but got=%d", want, got)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	slow := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	ts := newServer(t, slow)
+
+	tests := []struct {
+		name    string
+		options []emojiclient.Option
+	}{
+		{name: "timeout", options: []emojiclient.Option{emojiclient.WithTimeout(10 * time.Millisecond)}},
+		{name: "timeout-before-http-client", options: []emojiclient.Option{emojiclient.WithTimeout(10 * time.Millisecond), emojiclient.WithHTTPClient(&http.Client{})}},
+		{name: "timeout-after-http-client", options: []emojiclient.Option{emojiclient.WithHTTPClient(&http.Client{}), emojiclient.WithTimeout(10 * time.Millisecond)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := emojiclient.New(ts.URL, append(tt.options, emojiclient.WithRetry(0, 0))...)
+
+			if _, err := c.Suggest(context.Background(), "smil"); err == nil {
+				t.Errorf("want timeout error, but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package emojiclient
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/podhmo/emoji-api/api/client"
+)
+
+type retryConfig struct {
+	maxRetries int
+	interval   time.Duration
+}
+
+// retryDoer retries the request on the network errors, 429 and 5xx.
+// The interval is doubled for each retry, or Retry-After of the response if it is longer.
+type retryDoer struct {
+	doer   client.HttpRequestDoer
+	config retryConfig
+}
+
+func (d *retryDoer) Do(req *http.Request) (*http.Response, error) {
+	interval := d.config.interval
+	for i := 0; ; i++ {
+		attempt := req
+		if i > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("retry: the request body cannot be rewound")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("retry: rewind body: %w", err)
+			}
+			attempt = req.Clone(req.Context())
+			attempt.Body = body
+		}
+
+		res, err := d.doer.Do(attempt)
+		if i >= d.config.maxRetries || !shouldRetry(res, err) || req.Context().Err() != nil {
+			return res, err
+		}
+		wait := interval
+		if res != nil {
+			if d := retryAfter(res, time.Now()); d > wait {
+				wait = d
+			}
+			res.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		interval *= 2
+	}
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
+
+// retryAfter returns the duration of the Retry-After header, in seconds or HTTP-date (0 if not found).
+func retryAfter(res *http.Response, now time.Time) time.Duration {
+	v := res.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -1,28 +1,77 @@
 package emojilib
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"runtime/debug"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/enescakir/emoji"
+	"github.com/podhmo/emoji-api/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var (
+	translateUnknownAliases = metrics.Default.NewCounterVec("emojilib_translate_unknown_aliases_total", "the number of the aliases not translated by Translate()")
+	suggestResultSize       = metrics.Default.NewHistogramVec("emojilib_suggest_result_size", "the number of the suggestions returned by Suggest()", []float64{0, 1, 5, 10, 25, 50, 100, 250, 500, 1000})
+)
+
+var rxAlias = regexp.MustCompile(`:[a-z0-9_+\-]*[a-z][a-z0-9_+\-]*:`) // at least one letter (e.g. not "10:30:00")
+
+var tracer = otel.Tracer("github.com/podhmo/emoji-api/emojilib")
+
 // Trnaslate translates `:<emoji>:` to actual emoji unicode.
 func Translate(text string) string {
-	return emoji.Parse(text)
+	return TranslateContext(context.Background(), text)
+}
+
+// TranslateContext is Translate() with the span (emojilib.Translate) as the child of the span in ctx.
+func TranslateContext(ctx context.Context, text string) string {
+	_, span := tracer.Start(ctx, "emojilib.Translate", trace.WithAttributes(attribute.Int("emoji.text_length", len(text))))
+	defer span.End()
+
+	translated := emoji.Parse(text)
+	n := len(rxAlias.FindAllStringIndex(translated, -1))
+	if n > 0 {
+		translateUnknownAliases.With().Add(float64(n))
+	}
+	span.SetAttributes(attribute.Int("emoji.unknown_aliases", n))
+	return translated
+}
+
+// CountAliases returns the number of the `:<alias>:` tokens in text (including the unknown aliases).
+func CountAliases(text string) int {
+	return len(rxAlias.FindAllStringIndex(text, -1))
 }
 
 // Suggest returns the suggestions.
 func Suggest(prefix string, option SuggestOption) []Definition {
-	candidates := pool.Get().([]Definition) // more cache?
-	defer pool.Put(candidates)
+	return SuggestContext(context.Background(), prefix, option)
+}
+
+// SuggestContext is Suggest() with the span (emojilib.Suggest) as the child of the span in ctx.
+func SuggestContext(ctx context.Context, prefix string, option SuggestOption) []Definition {
+	_, span := tracer.Start(ctx, "emojilib.Suggest", trace.WithAttributes(
+		attribute.Int("emoji.prefix_length", len(prefix)),
+		attribute.Int("emoji.limit", option.Limit),
+		attribute.Bool("emoji.reverse", option.Reverse),
+	))
+	defer span.End()
+
+	candidates := definitions()
 
 	limit := option.Limit
 	reversed := option.Reverse
 
 	var r []Definition
-	if limit != 0 {
+	if limit > 0 {
 		r = make([]Definition, 0, limit)
 	}
 
@@ -51,11 +100,35 @@ func Suggest(prefix string, option SuggestOption) []Definition {
 			}
 		}
 	}
+	suggestResultSize.With().Observe(float64(len(r)))
+	span.SetAttributes(attribute.Int("emoji.result_count", len(r)))
+	return r
+}
+
+// List returns all definitions, sorted by alias.
+func List() []Definition {
+	return append([]Definition(nil), definitions()...)
+}
+
+// Lookup returns the definitions matched with the alias (e.g. ":dizzy:" or "dizzy") or the char (e.g. "💫").
+func Lookup(aliasOrChar string) []Definition {
+	candidates := definitions()
+
+	alias := aliasOrChar
+	if !strings.HasPrefix(alias, ":") {
+		alias = ":" + alias + ":"
+	}
+	var r []Definition
+	for _, p := range candidates {
+		if p.Alias == alias || p.Char == aliasOrChar {
+			r = append(r, p)
+		}
+	}
 	return r
 }
 
 type SuggestOption struct {
-	Limit   int
+	Limit   int // unlimited if 0 (or negative)
 	Reverse bool
 }
 
@@ -64,8 +137,16 @@ type Definition struct {
 	Char  string
 }
 
-var pool = &sync.Pool{
-	New: func() any {
+// the catalog is built lazily on the first use, or by Warmup(). (read only after built)
+var (
+	catalogOnce  sync.Once
+	catalog      []Definition
+	catalogHash  string
+	catalogReady atomic.Bool
+)
+
+func definitions() []Definition {
+	catalogOnce.Do(func() {
 		source := emoji.Map()
 		r := make([]Definition, len(source))
 		i := 0
@@ -74,6 +155,56 @@ var pool = &sync.Pool{
 			i++
 		}
 		sort.SliceStable(r, func(i, j int) bool { return r[i].Alias < r[j].Alias })
-		return r
-	},
+
+		h := sha256.New()
+		for _, d := range r {
+			h.Write([]byte(d.Alias + "\t" + d.Char + "\n"))
+		}
+		catalog = r
+		catalogHash = hex.EncodeToString(h.Sum(nil)[:16])
+		catalogReady.Store(true)
+	})
+	return catalog
 }
+
+// Warmup builds the catalog, if it is not built yet.
+func Warmup() {
+	definitions()
+}
+
+// Ready reports whether the catalog has been built (by Warmup() or the first Suggest()/List()/Lookup()).
+func Ready() bool {
+	return catalogReady.Load()
+}
+
+// CatalogSize returns the number of the definitions in the catalog (the catalog is built if needed).
+func CatalogSize() int {
+	return len(definitions())
+}
+
+// CatalogHash returns the hash of the definitions in the catalog (the hex of the sha256 prefix, the catalog is built if needed).
+// It is changed only if the definitions are changed (e.g. the upgrade of github.com/enescakir/emoji).
+func CatalogHash() string {
+	definitions()
+	return catalogHash
+}
+
+// CatalogVersion returns the version of the module providing the emoji definitions (github.com/enescakir/emoji),
+// or "unknown" if the build information is not available.
+func CatalogVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == catalogModule {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+const catalogModule = "github.com/enescakir/emoji"
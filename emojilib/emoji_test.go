@@ -27,6 +27,26 @@ func TestTranslate(t *testing.T) {
 	}
 }
 
+func TestCountAliases(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "known", text: "(o_0) :dizzy:", want: 1},
+		{name: "unknown", text: ":dizzy: :not-found-alias:", want: 2},
+		{name: "time", text: "at 10:30:00", want: 0},
+		{name: "none", text: "hello", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emojilib.CountAliases(tt.text); got != tt.want {
+				t.Errorf("CountAliases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSuggest(t *testing.T) {
 	type args struct {
 		prefix string
@@ -44,6 +64,9 @@ func TestSuggest(t *testing.T) {
 			want: []emojilib.Definition{{":diamond_shape_with_a_dot_inside:", "💠"}, {":diamond_suit:", "♦️"}, {":diamond_with_a_dot:", "💠"}}},
 		{name: "simple-with-reverse-limit3", args: args{prefix: ":di", option: emojilib.SuggestOption{Limit: 3, Reverse: true}},
 			want: []emojilib.Definition{{":dizzy_face:", "😵"}, {":dizzy:", "💫"}, {":diya_lamp:", "🪔"}}},
+		{name: "negative-limit-is-unlimited", args: args{prefix: ":diz", option: emojilib.SuggestOption{Limit: -1}},
+			want: []emojilib.Definition{{":dizzy:", "💫"}, {":dizzy_face:", "😵"}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -53,3 +76,36 @@ func TestSuggest(t *testing.T) {
 		})
 	}
 }
+
+func TestLookup(t *testing.T) {
+	type args struct {
+		aliasOrChar string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []emojilib.Definition
+	}{
+		{name: "alias", args: args{aliasOrChar: ":dizzy:"}, want: []emojilib.Definition{{Alias: ":dizzy:", Char: "💫"}}},
+		{name: "alias-without-colons", args: args{aliasOrChar: "dizzy"}, want: []emojilib.Definition{{Alias: ":dizzy:", Char: "💫"}}},
+		{name: "char", args: args{aliasOrChar: "💫"}, want: []emojilib.Definition{{Alias: ":dizzy:", Char: "💫"}}},
+		{name: "not-found", args: args{aliasOrChar: ":no-such-emoji:"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emojilib.Lookup(tt.args.aliasOrChar); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogHash(t *testing.T) {
+	got := emojilib.CatalogHash()
+	if len(got) != 32 {
+		t.Errorf("CatalogHash() = %q, want 32 hex characters", got)
+	}
+	if again := emojilib.CatalogHash(); again != got {
+		t.Errorf("CatalogHash() is not stable: %q != %q", got, again)
+	}
+}
@@ -0,0 +1,29 @@
+// Package logging carries the *slog.Logger in context.Context, for logging with the same fields in a request (e.g. request_id, operation).
+//
+//	logging.FromContext(ctx).Info("suggest", "result_count", len(suggestions))
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+// WithLogger returns the context carrying the logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger in the context, or slog.Default().
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With returns the context carrying the logger with the additional fields.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,317 @@
+// Package metrics is the minimal implementation of the metrics in the Prometheus text format (without external dependencies).
+//
+//	var requests = metrics.Default.NewCounterVec("http_requests_total", "the number of requests", "operation")
+//	requests.With("Suggest").Inc()
+//
+// The metrics are exposed by Registry.Handler() (e.g. GET /metrics).
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefBuckets is the default buckets of the histogram (seconds, for latency).
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the default registry.
+var Default = NewRegistry()
+
+// Registry is the set of the metrics.
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+type family interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// register registers the family, if the family having the same name is already registered, it is returned instead.
+func (r *Registry) register(f family) family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, x := range r.families {
+		if x.name() == f.name() {
+			if reflect.TypeOf(x) != reflect.TypeOf(f) {
+				panic(fmt.Sprintf("metrics: %q is already registered as %T", f.name(), x))
+			}
+			return x
+		}
+	}
+	r.families = append(r.families, f)
+	return f
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	families := append([]family(nil), r.families...)
+	r.mu.Unlock()
+	sort.Slice(families, func(i, j int) bool { return families[i].name() < families[j].name() })
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, f := range families {
+		f.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler returns the handler for GET /metrics.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w) // nolint
+	})
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// vec is the set of the series, keyed by the label values.
+type vec[T any] struct {
+	metricName string
+	help       string
+	labelNames []string
+	newSeries  func() *T
+
+	mu     sync.RWMutex
+	series map[string]*T
+	labels map[string][]string
+}
+
+func (v *vec[T]) name() string {
+	return v.metricName
+}
+
+func (v *vec[T]) with(labelValues []string) *T {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %q has %d labels, but %d values are passed", v.metricName, len(v.labelNames), len(labelValues)))
+	}
+	k := strings.Join(labelValues, "\xff")
+	v.mu.RLock()
+	s, ok := v.series[k]
+	v.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok := v.series[k]; ok {
+		return s
+	}
+	s = v.newSeries()
+	v.series[k] = s
+	v.labels[k] = append([]string(nil), labelValues...)
+	return s
+}
+
+// each calls fn for each series, in the order of the label values.
+func (v *vec[T]) each(fn func(labels string, s *T)) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	v.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v.mu.RLock()
+		s, values := v.series[k], v.labels[k]
+		v.mu.RUnlock()
+		fn(formatLabels(v.labelNames, values), s)
+	}
+}
+
+func (v *vec[T]) writeHeader(w *bufio.Writer, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", v.metricName, escapeHelp(v.help)) // nolint
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.metricName, typ)                // nolint
+}
+
+// CounterVec is the counters partitioned by the labels.
+type CounterVec struct {
+	vec[Counter]
+}
+
+// NewCounterVec registers the counter (the name should have the suffix "_total").
+// If the counter having the same name is already registered, it is returned.
+func (r *Registry) NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	v := &CounterVec{vec[Counter]{metricName: name, help: help, labelNames: labelNames, newSeries: func() *Counter { return &Counter{} }, series: map[string]*Counter{}, labels: map[string][]string{}}}
+	return r.register(v).(*CounterVec)
+}
+
+// With returns the counter for the label values (in the order of the label names).
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return v.with(labelValues)
+}
+
+func (v *CounterVec) write(w *bufio.Writer) {
+	v.writeHeader(w, "counter")
+	v.each(func(labels string, c *Counter) {
+		fmt.Fprintf(w, "%s%s %s\n", v.metricName, labels, formatFloat(c.Value())) // nolint
+	})
+}
+
+// Counter is the monotonically increasing value.
+type Counter struct {
+	bits uint64 // float64
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds the value (must be >= 0).
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	for {
+		old := atomic.LoadUint64(&c.bits)
+		if atomic.CompareAndSwapUint64(&c.bits, old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&c.bits))
+}
+
+// HistogramVec is the histograms partitioned by the labels.
+type HistogramVec struct {
+	vec[Histogram]
+	buckets []float64
+}
+
+// NewHistogramVec registers the histogram. If buckets is nil, DefBuckets is used.
+// If the histogram having the same name is already registered, it is returned.
+func (r *Registry) NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	v := &HistogramVec{buckets: buckets}
+	v.vec = vec[Histogram]{metricName: name, help: help, labelNames: labelNames, series: map[string]*Histogram{}, labels: map[string][]string{},
+		newSeries: func() *Histogram { return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))} },
+	}
+	return r.register(v).(*HistogramVec)
+}
+
+// With returns the histogram for the label values (in the order of the label names).
+func (v *HistogramVec) With(labelValues ...string) *Histogram {
+	return v.with(labelValues)
+}
+
+func (v *HistogramVec) write(w *bufio.Writer) {
+	v.writeHeader(w, "histogram")
+	v.each(func(labels string, h *Histogram) {
+		counts, count, sum := h.snapshot()
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", v.metricName, withLabel(labels, "le", formatFloat(le)), cumulative) // nolint
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", v.metricName, withLabel(labels, "le", "+Inf"), count) // nolint
+		fmt.Fprintf(w, "%s_sum%s %s\n", v.metricName, labels, formatFloat(sum))                  // nolint
+		fmt.Fprintf(w, "%s_count%s %d\n", v.metricName, labels, count)                           // nolint
+	})
+}
+
+// Histogram counts the observed values in the buckets.
+type Histogram struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	counts []uint64 // not cumulative
+	count  uint64
+	sum    float64
+}
+
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.buckets, v) // the first bucket, v <= le
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+func (h *Histogram) snapshot() ([]uint64, uint64, float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]uint64(nil), h.counts...), h.count, h.sum
+}
+
+func formatLabels(names []string, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(values[i]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func withLabel(labels string, name string, value string) string {
+	label := fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(value))
+	if labels == "" {
+		return "{" + label + "}"
+	}
+	return labels[:len(labels)-1] + "," + label + "}"
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// escapeLabelValue escapes \, " and \n (the other characters are written as is, in UTF-8).
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, +1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
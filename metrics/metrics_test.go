@@ -0,0 +1,48 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/metrics"
+)
+
+func TestRegistry(t *testing.T) {
+	r := metrics.NewRegistry()
+	requests := r.NewCounterVec("requests_total", "the number of requests", "operation")
+	latency := r.NewHistogramVec("latency_seconds", "the latency\nof requests", []float64{0.1, 1}, "operation")
+	unknown := r.NewCounterVec("unknown_total", "no labels")
+
+	requests.With("Suggest").Inc()
+	requests.With("Suggest").Inc()
+	requests.With(`Trans"late`).Add(0.5)
+	latency.With("Suggest").Observe(0.05)
+	latency.With("Suggest").Observe(0.5)
+	latency.With("Suggest").Observe(3)
+	unknown.With().Inc()
+
+	buf := new(bytes.Buffer)
+	if _, err := r.WriteTo(buf); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	want := `# HELP latency_seconds the latency\nof requests
+# TYPE latency_seconds histogram
+latency_seconds_bucket{operation="Suggest",le="0.1"} 1
+latency_seconds_bucket{operation="Suggest",le="1"} 2
+latency_seconds_bucket{operation="Suggest",le="+Inf"} 3
+latency_seconds_sum{operation="Suggest"} 3.55
+latency_seconds_count{operation="Suggest"} 3
+# HELP requests_total the number of requests
+# TYPE requests_total counter
+requests_total{operation="Suggest"} 2
+requests_total{operation="Trans\"late"} 0.5
+# HELP unknown_total no labels
+# TYPE unknown_total counter
+unknown_total 1
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteTo() mismatch (-want +got):\n%s", diff)
+	}
+}
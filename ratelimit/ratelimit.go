@@ -0,0 +1,140 @@
+// Package ratelimit is the token bucket rate limiter, with the pluggable storage of the buckets.
+//
+//	store := ratelimit.NewMemoryStore()
+//	result, err := store.Take(ctx, "Suggest:ip:127.0.0.1", ratelimit.Limit{Rate: 20, Burst: 40}, time.Now())
+//	if !result.Allowed { /* retry after result.RetryAfter */ }
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is the refill rate (tokens per second) and the capacity of the bucket.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseLimit parses the limit formatted as "<n>/<s|m|h>[:<burst>]" (e.g. "20/s:40", "60/m"). The burst is n if omitted.
+func ParseLimit(s string) (Limit, error) {
+	spec, burst, hasBurst := strings.Cut(strings.TrimSpace(s), ":")
+	count, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q: want <n>/<s|m|h>[:<burst>]", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: the count should be a positive integer", s)
+	}
+
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid limit %q: unexpected unit %q (s|m|h)", s, unit)
+	}
+
+	limit := Limit{Rate: float64(n) / per.Seconds(), Burst: n}
+	if hasBurst {
+		b, err := strconv.Atoi(burst)
+		if err != nil || b <= 0 {
+			return Limit{}, fmt.Errorf("invalid limit %q: the burst should be a positive integer", s)
+		}
+		limit.Burst = b
+	}
+	return limit, nil
+}
+
+// Result is the result of Store.Take().
+type Result struct {
+	Allowed    bool
+	Remaining  int           // the number of the tokens left in the bucket
+	RetryAfter time.Duration // the time until the next token is available (if not allowed)
+}
+
+// Store keeps the buckets, and takes a token from the bucket of the key.
+// The implementation other than MemoryStore (e.g. shared by the replicas) can be plugged into the middleware.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// MemoryStore is the Store in the process memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit // the limit of the last take (for sweeping)
+}
+
+// sweepInterval is the number of the takes between the sweeps of the full buckets (same as the absent ones).
+const sweepInterval = 1024
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take takes a token from the bucket of the key, the bucket is full at first.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%sweepInterval == 0 {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = refill(b, limit, now)
+	b.last = now
+	b.limit = limit
+
+	if b.tokens < 1 {
+		retryAfter := time.Duration(math.Ceil((1 - b.tokens) / limit.Rate * float64(time.Second)))
+		return Result{Allowed: false, Remaining: 0, RetryAfter: retryAfter}, nil
+	}
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// Len returns the number of the buckets kept in the store.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for k, b := range s.buckets {
+		if refill(b, b.limit, now) >= float64(b.limit.Burst) {
+			delete(s.buckets, k)
+		}
+	}
+}
+
+func refill(b *bucket, limit Limit, now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+}
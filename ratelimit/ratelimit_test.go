@@ -0,0 +1,104 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/ratelimit"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ratelimit.Limit
+		wantErr bool
+	}{
+		{input: "20/s:40", want: ratelimit.Limit{Rate: 20, Burst: 40}},
+		{input: "60/m", want: ratelimit.Limit{Rate: 1, Burst: 60}},
+		{input: "3600/h:10", want: ratelimit.Limit{Rate: 1, Burst: 10}},
+		{input: "20", wantErr: true},
+		{input: "20/d", wantErr: true},
+		{input: "0/s", wantErr: true},
+		{input: "20/s:x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ratelimit.ParseLimit(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("want error, but nil (got=%+v)", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseLimit() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 2, Burst: 2} // 1 token per 500ms
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type take struct {
+		key     string
+		elapsed time.Duration // since the start
+		want    ratelimit.Result
+	}
+	store := ratelimit.NewMemoryStore()
+	for i, x := range []take{
+		{key: "a", want: ratelimit.Result{Allowed: true, Remaining: 1}},
+		{key: "a", want: ratelimit.Result{Allowed: true, Remaining: 0}},
+		{key: "a", want: ratelimit.Result{Allowed: false, RetryAfter: 500 * time.Millisecond}},
+		{key: "b", want: ratelimit.Result{Allowed: true, Remaining: 1}}, // the other key has its own bucket
+		{key: "a", elapsed: 250 * time.Millisecond, want: ratelimit.Result{Allowed: false, RetryAfter: 250 * time.Millisecond}},
+		{key: "a", elapsed: 500 * time.Millisecond, want: ratelimit.Result{Allowed: true, Remaining: 0}},
+		{key: "a", elapsed: 10 * time.Second, want: ratelimit.Result{Allowed: true, Remaining: 1}}, // not over the burst
+	} {
+		got, err := store.Take(ctx, x.key, limit, now.Add(x.elapsed))
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %+v", i, err)
+		}
+		if diff := cmp.Diff(x.want, got); diff != "" {
+			t.Errorf("%d: Take(%q) mismatch (-want +got):\n%s", i, x.key, diff)
+		}
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := ratelimit.NewMemoryStore()
+
+	// the bucket of the strict limit is kept, even if swept by the take with the loose limit
+	strict := ratelimit.Limit{Rate: 0.001, Burst: 1}
+	if _, err := store.Take(ctx, "strict", strict, now); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	loose := ratelimit.Limit{Rate: 1000, Burst: 1000}
+	for i := 0; i < 2048; i++ {
+		if _, err := store.Take(ctx, "loose", loose, now.Add(time.Second)); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+	}
+	if got, _ := store.Take(ctx, "strict", strict, now.Add(2*time.Second)); got.Allowed {
+		t.Errorf("the strict bucket is swept: %+v", got)
+	}
+
+	// the full buckets are swept
+	for i := 0; i < 2048; i++ {
+		if _, err := store.Take(ctx, "loose", loose, now.Add(time.Hour)); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+	}
+	if want, got := 1, store.Len(); want != got { // "strict" (refilled after an hour) is swept, only "loose" is kept
+		t.Errorf("len: want=%d, but got=%d", want, got)
+	}
+}
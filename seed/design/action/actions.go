@@ -4,20 +4,57 @@ import "github.com/podhmo/emoji-api/seed/design"
 
 var b = design.Builder
 
+// the actions are routed by the router (e.g. emoji.Post(<path>, <action>)), and registered by seed/tools/gen-doc.
 var (
-	EmojiTranslate = b.Action("translate",
-		b.Input(b.Body(b.Object(b.Field("text", b.String())))),
+	emoji  = tagged("emoji").Secured(design.ScopeRead)
+	system = tagged("system")
+)
+
+var (
+	EmojiTranslate = emoji.Post("/emoji/translate", b.Action("translate",
+		b.Input(b.Body(b.Object(
+			b.Field("text", b.String().MaxLength(10000)).Doc("at most 1000 :<alias>: tokens, and the request body is at most 256KiB"),
+		))),
 		b.Output(b.String()),
-	).Doc(":<alias>:のような表現を含んだ文字列をemojiを使った文字列に変換する")
+	).Doc(":<alias>:のような表現を含んだ文字列をemojiを使った文字列に変換する"))
 
-	EmojiSuggest = b.Action("suggest",
+	EmojiSuggest = emoji.Produces("application/x-ndjson", "text/csv").Post("/emoji/suggest", b.Action("suggest",
 		b.Input(b.Body(
 			b.Object(
 				b.Field("prefix", b.String()),
 				b.Field("sort", b.String().Enum([]string{"asc", "desc"}).Default("asc")),
-				b.Field("limit", b.Int()).Required(false),
+				b.Field("limit", design.Zero(b.Int().Minimum(0), "minimum")).Required(false).Doc("the maximum number of the suggestions, 0 means no limit"),
 			)),
 		),
 		b.Output(b.Array(design.EmojiDefinition)),
-	).Doc("先頭一致で対応する文字列を探す")
+	).Doc("先頭一致で対応する文字列を探す"))
+
+	EmojiList = emoji.Cached().Get("/emoji", b.Action("list",
+		b.Input(
+			b.Param("offset", design.Zero(b.Int().Minimum(0).Default(0), "minimum", "default")).Required(false).Doc("the number of the definitions to skip"),
+			b.Param("limit", b.Int().Minimum(1).Maximum(1000).Default(100)).Required(false).Doc("the number of the definitions in the page"),
+		),
+		b.Output(design.EmojiPage),
+	).Doc("the emoji catalog sorted by alias, paged by offset and limit (with the ETag of the catalog)"))
+
+	EmojiExport = emoji.Cached().Produces("application/x-ndjson", "text/csv").Get("/emoji/export", b.Action("export",
+		b.Input(
+			b.Param("format", b.String().Enum([]string{"json", "ndjson", "csv"})).Required(false).Doc("the representation, selected by the Accept header if omitted (default: json)"),
+		),
+		b.Output(b.Array(design.EmojiDefinition)),
+	).Doc("all emoji definitions of the catalog in JSON, NDJSON or CSV (with the ETag of the catalog), for caching the catalog on the client"))
+)
+
+var (
+	SystemHealthz = system.Get("/healthz", b.Action("healthz",
+		b.Output(design.Health),
+	).Doc("liveness probe, always ok while the server is running"))
+
+	SystemReadyz = system.Get("/readyz", b.Action("readyz",
+		b.Output(design.Health),
+	).Doc("readiness probe, 503 until the emoji catalog has been built"))
+
+	SystemVersion = system.Get("/version", b.Action("version",
+		b.Output(design.BuildInfo),
+	).Doc("the module version, VCS revision and the emoji catalog size/version"))
 )
@@ -0,0 +1,189 @@
+package action
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/podhmo/gos/openapigen"
+)
+
+// Route is the method, path and tags of the action.
+type Route struct {
+	Method string
+	Path   string
+	Tags   []string
+	Scopes []string // the scopes of the API key required by the action, no auth if nil (see design.SecuritySchemes)
+	Action *openapigen.Action
+
+	Produces []string // the media types of the 200 response other than application/json (the body is the text), see router.Produces()
+	Cached   bool     // the 200 response has ETag, and 304 is returned for If-None-Match, see router.Cached()
+}
+
+var routes []*Route
+
+// Routes returns the routes registered in this package, in the order of definition.
+func Routes() []*Route {
+	return append([]*Route(nil), routes...)
+}
+
+// Register registers the routes to the router (used by seed/tools/gen-doc).
+func Register(r *openapigen.Router) error {
+	seen := map[string]bool{}
+	for _, x := range routes {
+		k := strings.ToUpper(x.Method) + " " + x.Path
+		if seen[k] {
+			return fmt.Errorf("duplicated route: %s", k)
+		}
+		seen[k] = true
+		r.Tagged(x.Tags...).Method(x.Method, x.Path, x.Action)
+	}
+	return nil
+}
+
+// router declares the routes next to the definition of the actions.
+//
+//	EmojiTranslate = emoji.Post("/emoji/translate", b.Action("translate", ...))
+type router struct {
+	tags     []string
+	scopes   []string
+	produces []string
+	cached   bool
+}
+
+func tagged(tags ...string) *router {
+	return &router{tags: tags}
+}
+
+// Secured returns the router whose actions require the API key with the scopes.
+//
+//	emoji = tagged("emoji").Secured(design.ScopeRead)
+func (r *router) Secured(scopes ...string) *router {
+	x := *r
+	x.scopes = append([]string{}, scopes...)
+	return &x
+}
+
+// Produces returns the router whose actions respond with the media types other than application/json, too (e.g. text/csv).
+//
+//	emoji.Produces("text/csv").Get("/emoji/export", ...)
+func (r *router) Produces(mediaTypes ...string) *router {
+	x := *r
+	x.produces = append(append([]string{}, r.produces...), mediaTypes...)
+	return &x
+}
+
+// Cached returns the router whose actions support the conditional request,
+// the If-None-Match header, the ETag header of the 200 response, and the 304 response are declared.
+//
+//	emoji.Cached().Get("/emoji", ...)
+func (r *router) Cached() *router {
+	x := *r
+	x.cached = true
+	return &x
+}
+
+func (r *router) Method(method string, path string, action *openapigen.Action) *openapigen.Action {
+	routes = append(routes, &Route{Method: method, Path: path, Tags: r.tags, Scopes: r.scopes, Action: action, Produces: r.produces, Cached: r.cached})
+	return action
+}
+func (r *router) Get(path string, action *openapigen.Action) *openapigen.Action {
+	return r.Method("get", path, action)
+}
+func (r *router) Post(path string, action *openapigen.Action) *openapigen.Action {
+	return r.Method("post", path, action)
+}
+func (r *router) Put(path string, action *openapigen.Action) *openapigen.Action {
+	return r.Method("put", path, action)
+}
+func (r *router) Patch(path string, action *openapigen.Action) *openapigen.Action {
+	return r.Method("patch", path, action)
+}
+func (r *router) Delete(path string, action *openapigen.Action) *openapigen.Action {
+	return r.Method("delete", path, action)
+}
+
+// Unrouted returns the actions (b.Action(...)) which are not passed to the routing methods, in the package dir.
+// Each entry is formatted as "<position>: <action name>".
+func Unrouted(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	notTest := func(info fs.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(fset, dir, notTest, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", dir, err)
+	}
+
+	var r []string
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			routed := map[*ast.CallExpr]bool{}
+			ast.Inspect(f, func(node ast.Node) bool {
+				if call, ok := node.(*ast.CallExpr); ok && isRoutingCall(call) {
+					if action := findActionCall(call.Args[len(call.Args)-1]); action != nil {
+						routed[action] = true
+					}
+				}
+				return true
+			})
+			ast.Inspect(f, func(node ast.Node) bool {
+				if call, ok := node.(*ast.CallExpr); ok && isActionCall(call) && !routed[call] {
+					r = append(r, fmt.Sprintf("%s: %s", fset.Position(call.Pos()), actionName(call)))
+				}
+				return true
+			})
+		}
+	}
+	sort.Strings(r)
+	return r, nil
+}
+
+// isRoutingCall reports whether the call is <router>.Post(path, action) or the like.
+func isRoutingCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) < 2 {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "Get", "Post", "Put", "Patch", "Delete", "Method":
+		return true
+	}
+	return false
+}
+
+// isActionCall reports whether the call is b.Action(name, ...).
+func isActionCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Action" && len(call.Args) > 0
+}
+
+// findActionCall unwraps the method chain (e.g. b.Action(...).Doc(...)) and returns the b.Action(...) call.
+func findActionCall(expr ast.Expr) *ast.CallExpr {
+	for {
+		switch x := expr.(type) {
+		case *ast.ParenExpr:
+			expr = x.X
+		case *ast.CallExpr:
+			if isActionCall(x) {
+				return x
+			}
+			sel, ok := x.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return nil
+			}
+			expr = sel.X
+		default:
+			return nil
+		}
+	}
+}
+
+func actionName(call *ast.CallExpr) string {
+	if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+		return lit.Value
+	}
+	return "<unknown>"
+}
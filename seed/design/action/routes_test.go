@@ -0,0 +1,43 @@
+package action_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/emoji-api/seed/design/action"
+)
+
+func TestRoutes(t *testing.T) {
+	unrouted, err := action.Unrouted(".")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if len(unrouted) > 0 {
+		t.Errorf("the actions are defined but never routed: %v", unrouted)
+	}
+}
+
+func TestUnrouted(t *testing.T) {
+	dir := t.TempDir()
+	code := `package action
+
+var (
+	Routed = emoji.Post("/routed", b.Action("routed").Doc("routed"))
+	Unrouted = b.Action("unrouted").Doc("unrouted")
+)
+`
+	if err := os.WriteFile(filepath.Join(dir, "actions.go"), []byte(code), 0644); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	got, err := action.Unrouted(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	want := []string{filepath.Join(dir, "actions.go") + `:5:13: "unrouted"`}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unrouted() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -17,4 +17,24 @@ var (
 		b.Field("alias", b.String().Example(":dizzy:")),
 		b.Field("char", b.String().Example("💫")),
 	))
+
+	EmojiPage = openapigen.Define("EmojiPage", b.Object(
+		b.Field("items", b.Array(EmojiDefinition)).Doc("the definitions sorted by alias"),
+		b.Field("total", b.Int()).Doc("the number of the definitions in the catalog"),
+		b.Field("nextOffset", b.Int()).Doc("the offset of the next page, absent on the last page").Required(false),
+	)).Doc("a page of the emoji catalog")
+)
+
+// system
+var (
+	Health = openapigen.Define("Health", b.Object(
+		b.Field("status", b.String().Example("ok")),
+	)).Doc("the status of the server")
+
+	BuildInfo = openapigen.Define("BuildInfo", b.Object(
+		b.Field("version", b.String().Example("v0.1.0")).Doc("the module version ((devel) if built from the source tree)"),
+		b.Field("revision", b.String().Example("9bc4ee2")).Doc("the VCS revision, if embedded in the binary").Required(false),
+		b.Field("catalogSize", b.Int()).Doc("the number of the emoji definitions"),
+		b.Field("catalogVersion", b.String().Example("v1.0.0")).Doc("the version of the emoji catalog (github.com/enescakir/emoji)"),
+	)).Doc("the build information of the server")
 )
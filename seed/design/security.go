@@ -0,0 +1,25 @@
+package design
+
+// SecurityScheme is the entry of components.securitySchemes (openapigen has no builder for it, gen-doc adds them).
+type SecurityScheme struct {
+	Name   string // the key in components.securitySchemes (e.g. bearerAuth)
+	Type   string // http or apiKey
+	Scheme string // for http (e.g. bearer)
+	In     string // for apiKey (e.g. header)
+	Param  string // for apiKey, the name of the header
+	Doc    string
+}
+
+// the scopes of the API keys
+const (
+	ScopeRead  = "read"  // suggest, translate
+	ScopeWrite = "write" // custom emoji
+)
+
+// auth (the both schemes accept the same API key, the operations require one of them)
+var (
+	BearerAuth = SecurityScheme{Name: "bearerAuth", Type: "http", Scheme: "bearer", Doc: "the API key as the bearer token (Authorization: Bearer <key>)"}
+	APIKeyAuth = SecurityScheme{Name: "apiKeyAuth", Type: "apiKey", In: "header", Param: "X-API-Key", Doc: "the API key in the X-API-Key header"}
+
+	SecuritySchemes = []SecurityScheme{BearerAuth, APIKeyAuth}
+)
@@ -0,0 +1,33 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/podhmo/gos/openapigen"
+)
+
+var zeros = map[*openapigen.Int][]string{}
+
+// Zero keeps the zero-valued keywords of the integer type (e.g. minimum: 0) in the schema.
+// openapigen omits them (the fields of openapigen.IntMetadata are omitempty), gen-doc adds them back (see ZeroKeywords).
+//
+//	b.Param("offset", design.Zero(b.Int().Minimum(0).Default(0), "minimum", "default"))
+func Zero(t *openapigen.Int, keywords ...string) *openapigen.Int {
+	for _, k := range keywords {
+		switch k {
+		case "minimum", "maximum", "default":
+		default:
+			panic(fmt.Sprintf("unexpected keyword: %q (minimum|maximum|default)", k))
+		}
+	}
+	zeros[t] = append(zeros[t], keywords...)
+	return t
+}
+
+// ZeroKeywords returns the keywords marked by Zero, nil if the type is not marked.
+func ZeroKeywords(t openapigen.Type) []string {
+	if t, ok := t.(*openapigen.Int); ok {
+		return zeros[t]
+	}
+	return nil
+}
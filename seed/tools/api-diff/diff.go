@@ -0,0 +1,543 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	KindBreaking    = "breaking"
+	KindNonBreaking = "non-breaking"
+)
+
+// Change is a difference between the base document and the head document.
+type Change struct {
+	Kind    string `json:"kind"`    // breaking or non-breaking
+	Path    string `json:"path"`    // e.g. POST /emoji/suggest requestBody.sort.enum
+	Message string `json:"message"` // e.g. enum value "desc" is removed
+}
+
+// Report is the result of Diff.
+type Report struct {
+	Breaking    int       `json:"breaking"`
+	NonBreaking int       `json:"nonBreaking"`
+	Changes     []*Change `json:"changes"`
+}
+
+// HasBreakingChanges reports whether the report includes the breaking changes.
+func (r *Report) HasBreakingChanges() bool {
+	return r.Breaking > 0
+}
+
+// direction is where the schema is used. The same change is breaking or not, depending on the direction.
+// e.g. adding an enum value is safe for the request, but breaks the clients for the response.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+type differ struct {
+	report  *Report
+	visited map[[2]*openapi3.Schema]bool
+}
+
+// Diff compares the operations of the two documents, and classifies the changes.
+func Diff(base, head *openapi3.T) *Report {
+	d := &differ{report: &Report{Changes: []*Change{}}, visited: map[[2]*openapi3.Schema]bool{}}
+
+	baseOps := operations(base)
+	headOps := operations(head)
+	for _, k := range sortedKeys(baseOps) {
+		headOp, ok := headOps[k]
+		if !ok {
+			d.add(KindBreaking, k, "operation is removed")
+			continue
+		}
+		d.operation(k, baseOps[k], headOp)
+		d.security(k, security(base, baseOps[k]), security(head, headOp))
+	}
+	for _, k := range sortedKeys(headOps) {
+		if _, ok := baseOps[k]; !ok {
+			d.add(KindNonBreaking, k, "operation is added")
+		}
+	}
+	return d.report
+}
+
+func (d *differ) add(kind, path, format string, args ...any) {
+	d.report.Changes = append(d.report.Changes, &Change{Kind: kind, Path: path, Message: fmt.Sprintf(format, args...)})
+	if kind == KindBreaking {
+		d.report.Breaking++
+	} else {
+		d.report.NonBreaking++
+	}
+}
+
+// operations returns the operations keyed by "<METHOD> <path>".
+func operations(doc *openapi3.T) map[string]*openapi3.Operation {
+	r := map[string]*openapi3.Operation{}
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			r[strings.ToUpper(method)+" "+path] = op
+		}
+	}
+	return r
+}
+
+func (d *differ) operation(path string, base, head *openapi3.Operation) {
+	// parameters
+	baseParams := parameters(base)
+	headParams := parameters(head)
+	for _, k := range sortedKeys(baseParams) {
+		p := path + " " + k
+		headParam, ok := headParams[k]
+		if !ok {
+			d.add(KindBreaking, p, "parameter is removed")
+			continue
+		}
+		baseParam := baseParams[k]
+		if !baseParam.Required && headParam.Required {
+			d.add(KindBreaking, p, "parameter becomes required")
+		} else if baseParam.Required && !headParam.Required {
+			d.add(KindNonBreaking, p, "parameter becomes optional")
+		}
+		d.schema(p, baseParam.Schema, headParam.Schema, request)
+	}
+	for _, k := range sortedKeys(headParams) {
+		if _, ok := baseParams[k]; !ok {
+			if headParams[k].Required {
+				d.add(KindBreaking, path+" "+k, "required parameter is added")
+			} else {
+				d.add(KindNonBreaking, path+" "+k, "optional parameter is added")
+			}
+		}
+	}
+
+	// request body
+	switch baseBody, headBody := requestBody(base), requestBody(head); {
+	case baseBody == nil && headBody != nil:
+		if headBody.Required {
+			d.add(KindBreaking, path+" requestBody", "required request body is added")
+		} else {
+			d.add(KindNonBreaking, path+" requestBody", "optional request body is added")
+		}
+	case baseBody != nil && headBody == nil:
+		d.add(KindBreaking, path+" requestBody", "request body is removed")
+	case baseBody != nil && headBody != nil:
+		if !baseBody.Required && headBody.Required {
+			d.add(KindBreaking, path+" requestBody", "request body becomes required")
+		}
+		d.content(path+" requestBody", baseBody.Content, headBody.Content, request)
+	}
+
+	// responses
+	for _, code := range sortedKeys(base.Responses) {
+		p := path + " responses." + code
+		headRes, ok := head.Responses[code]
+		if !ok {
+			d.add(KindBreaking, p, "response is removed")
+			continue
+		}
+		if base.Responses[code].Value != nil && headRes.Value != nil {
+			d.content(p, base.Responses[code].Value.Content, headRes.Value.Content, response)
+		}
+	}
+	for _, code := range sortedKeys(head.Responses) {
+		if _, ok := base.Responses[code]; !ok {
+			d.add(KindNonBreaking, path+" responses."+code, "response is added")
+		}
+	}
+}
+
+// security returns the security requirements of the operation, or the top-level ones if not overridden.
+// Each requirement is formatted as "<scheme>[<scope>,...]" (and joined with " + " if it has multiple schemes).
+// The empty scopes are filled by x-required-scopes of the operation (the scopes of http/apiKey schemes must be empty in OAS 3.0).
+func security(doc *openapi3.T, op *openapi3.Operation) []string {
+	requirements := doc.Security
+	if op.Security != nil {
+		requirements = *op.Security
+	}
+	required := requiredScopes(op)
+	r := make([]string, 0, len(requirements))
+	for _, req := range requirements {
+		if len(req) == 0 { // {} means the auth is optional
+			r = append(r, "{}")
+			continue
+		}
+		schemes := make([]string, 0, len(req))
+		for _, name := range sortedKeys(req) {
+			scopes := append([]string{}, req[name]...)
+			if len(scopes) == 0 {
+				scopes = append(scopes, required...)
+			}
+			sort.Strings(scopes)
+			schemes = append(schemes, name+"["+strings.Join(scopes, ",")+"]")
+		}
+		r = append(r, strings.Join(schemes, " + "))
+	}
+	return r
+}
+
+// requiredScopes returns x-required-scopes of the operation.
+func requiredScopes(op *openapi3.Operation) []string {
+	v, ok := op.Extensions["x-required-scopes"]
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(v) // json.RawMessage or []any
+	if err != nil {
+		return nil
+	}
+	var scopes []string
+	if err := json.Unmarshal(b, &scopes); err != nil {
+		return nil
+	}
+	return scopes
+}
+
+func (d *differ) security(path string, base, head []string) {
+	p := path + " security"
+	switch {
+	case len(base) == 0 && len(head) == 0:
+		return
+	case len(base) == 0:
+		d.add(KindBreaking, p, "security requirement is added: %s", strings.Join(head, " | "))
+		return
+	case len(head) == 0:
+		d.add(KindNonBreaking, p, "security requirement is removed")
+		return
+	}
+
+	baseSet := map[string]bool{}
+	for _, x := range base {
+		baseSet[x] = true
+	}
+	headSet := map[string]bool{}
+	for _, x := range head {
+		headSet[x] = true
+	}
+	for _, x := range base {
+		if !headSet[x] {
+			d.add(KindBreaking, p, "security alternative is removed: %s", x)
+		}
+	}
+	for _, x := range head {
+		if !baseSet[x] {
+			d.add(KindNonBreaking, p, "security alternative is added: %s", x)
+		}
+	}
+}
+
+func (d *differ) content(path string, base, head openapi3.Content, dir direction) {
+	for _, contentType := range sortedKeys(base) {
+		headMedia, ok := head[contentType]
+		if !ok {
+			d.add(KindBreaking, path, "content type %q is removed", contentType)
+			continue
+		}
+		d.schema(path, base[contentType].Schema, headMedia.Schema, dir)
+	}
+	for _, contentType := range sortedKeys(head) {
+		if _, ok := base[contentType]; !ok {
+			d.add(KindNonBreaking, path, "content type %q is added", contentType)
+		}
+	}
+}
+
+func (d *differ) schema(path string, baseRef, headRef *openapi3.SchemaRef, dir direction) {
+	if baseRef == nil || headRef == nil || baseRef.Value == nil || headRef.Value == nil {
+		return
+	}
+	base, head := baseRef.Value, headRef.Value
+	if d.visited[[2]*openapi3.Schema{base, head}] { // recursive schema
+		return
+	}
+	d.visited[[2]*openapi3.Schema{base, head}] = true
+	defer delete(d.visited, [2]*openapi3.Schema{base, head})
+
+	if base.Type != head.Type {
+		d.add(KindBreaking, path, "type is changed: %q -> %q", base.Type, head.Type)
+		return
+	}
+	if base.Format != head.Format && base.Format != "" {
+		d.add(KindBreaking, path, "format is changed: %q -> %q", base.Format, head.Format)
+	}
+	if base.Nullable != head.Nullable {
+		// nullable in the request is relaxing, in the response is the new value for the clients
+		if (head.Nullable && dir == response) || (!head.Nullable && dir == request) {
+			d.add(KindBreaking, path, "nullable is changed: %v -> %v", base.Nullable, head.Nullable)
+		} else {
+			d.add(KindNonBreaking, path, "nullable is changed: %v -> %v", base.Nullable, head.Nullable)
+		}
+	}
+	d.enum(path+".enum", base.Enum, head.Enum, dir)
+	d.constraints(path, base, head, dir)
+
+	// properties
+	baseRequired := set(base.Required)
+	headRequired := set(head.Required)
+	for _, name := range sortedKeys(base.Properties) {
+		p := path + "." + name
+		headProp, ok := head.Properties[name]
+		if !ok {
+			d.add(KindBreaking, p, "property is removed")
+			continue
+		}
+		switch {
+		case !baseRequired[name] && headRequired[name]:
+			if dir == request {
+				d.add(KindBreaking, p, "property becomes required")
+			} else {
+				d.add(KindNonBreaking, p, "property becomes required")
+			}
+		case baseRequired[name] && !headRequired[name]:
+			if dir == response {
+				d.add(KindBreaking, p, "property becomes optional")
+			} else {
+				d.add(KindNonBreaking, p, "property becomes optional")
+			}
+		}
+		d.schema(p, base.Properties[name], headProp, dir)
+	}
+	for _, name := range sortedKeys(head.Properties) {
+		if _, ok := base.Properties[name]; ok {
+			continue
+		}
+		if dir == request && headRequired[name] {
+			d.add(KindBreaking, path+"."+name, "required property is added")
+		} else {
+			d.add(KindNonBreaking, path+"."+name, "property is added")
+		}
+	}
+
+	d.additionalProperties(path, base.AdditionalProperties, head.AdditionalProperties, dir)
+
+	d.schema(path+"[]", base.Items, head.Items, dir)
+
+	d.composition(path, "allOf", base.AllOf, head.AllOf, true, dir)
+	d.composition(path, "oneOf", base.OneOf, head.OneOf, false, dir)
+	d.composition(path, "anyOf", base.AnyOf, head.AnyOf, false, dir)
+}
+
+// restrict adds the change of the restriction. The tightened restriction breaks the clients sending the values
+// accepted before (request), the loosened one breaks the clients expecting the values restricted (response).
+func (d *differ) restrict(path string, tightened bool, dir direction, format string, args ...any) {
+	if tightened == (dir == request) {
+		d.add(KindBreaking, path, format, args...)
+	} else {
+		d.add(KindNonBreaking, path, format, args...)
+	}
+}
+
+// constraints compares the validation keywords of the value (length, range, items and pattern).
+func (d *differ) constraints(path string, base, head *openapi3.Schema, dir direction) {
+	d.lowerBound(path, "minLength", float64(base.MinLength), float64(head.MinLength), dir)
+	d.upperBound(path, "maxLength", uint64ToFloat(base.MaxLength), uint64ToFloat(head.MaxLength), dir)
+	d.lowerBound(path, "minItems", float64(base.MinItems), float64(head.MinItems), dir)
+	d.upperBound(path, "maxItems", uint64ToFloat(base.MaxItems), uint64ToFloat(head.MaxItems), dir)
+
+	switch baseMin, headMin := base.Min, head.Min; {
+	case baseMin == nil && headMin != nil:
+		d.restrict(path, true, dir, "minimum is added: %v", *headMin)
+	case baseMin != nil && headMin == nil:
+		d.restrict(path, false, dir, "minimum is removed: %v", *baseMin)
+	case baseMin != nil && headMin != nil && (*baseMin != *headMin || base.ExclusiveMin != head.ExclusiveMin):
+		tightened := *headMin > *baseMin || (*headMin == *baseMin && head.ExclusiveMin)
+		d.restrict(path, tightened, dir, "minimum is changed: %s -> %s", bound(*baseMin, base.ExclusiveMin), bound(*headMin, head.ExclusiveMin))
+	}
+	switch baseMax, headMax := base.Max, head.Max; {
+	case baseMax == nil && headMax != nil:
+		d.restrict(path, true, dir, "maximum is added: %v", *headMax)
+	case baseMax != nil && headMax == nil:
+		d.restrict(path, false, dir, "maximum is removed: %v", *baseMax)
+	case baseMax != nil && headMax != nil && (*baseMax != *headMax || base.ExclusiveMax != head.ExclusiveMax):
+		tightened := *headMax < *baseMax || (*headMax == *baseMax && head.ExclusiveMax)
+		d.restrict(path, tightened, dir, "maximum is changed: %s -> %s", bound(*baseMax, base.ExclusiveMax), bound(*headMax, head.ExclusiveMax))
+	}
+
+	switch {
+	case base.Pattern == head.Pattern:
+	case base.Pattern == "":
+		d.restrict(path, true, dir, "pattern is added: %q", head.Pattern)
+	case head.Pattern == "":
+		d.restrict(path, false, dir, "pattern is removed: %q", base.Pattern)
+	default: // cannot tell whether the new pattern accepts the values matched before
+		d.add(KindBreaking, path, "pattern is changed: %q -> %q", base.Pattern, head.Pattern)
+	}
+}
+
+// lowerBound compares the lower bound, 0 means no restriction (e.g. minLength).
+func (d *differ) lowerBound(path, name string, base, head float64, dir direction) {
+	switch {
+	case base == head:
+	case base == 0:
+		d.restrict(path, true, dir, "%s is added: %v", name, head)
+	case head == 0:
+		d.restrict(path, false, dir, "%s is removed: %v", name, base)
+	default:
+		d.restrict(path, head > base, dir, "%s is changed: %v -> %v", name, base, head)
+	}
+}
+
+// upperBound compares the upper bound, nil means no restriction (e.g. maxLength).
+func (d *differ) upperBound(path, name string, base, head *float64, dir direction) {
+	switch {
+	case base == nil && head == nil:
+	case base == nil:
+		d.restrict(path, true, dir, "%s is added: %v", name, *head)
+	case head == nil:
+		d.restrict(path, false, dir, "%s is removed: %v", name, *base)
+	case *base != *head:
+		d.restrict(path, *head < *base, dir, "%s is changed: %v -> %v", name, *base, *head)
+	}
+}
+
+// additionalProperties compares the properties not listed in properties, allowed (default) < schema < forbidden (false).
+func (d *differ) additionalProperties(path string, base, head openapi3.AdditionalProperties, dir direction) {
+	p := path + ".additionalProperties"
+	baseLevel, headLevel := additionalLevel(base), additionalLevel(head)
+	if baseLevel == headLevel {
+		if baseLevel == "schema" {
+			d.schema(p, base.Schema, head.Schema, dir)
+		}
+		return
+	}
+	d.restrict(p, additionalLevels[headLevel] > additionalLevels[baseLevel], dir, "additionalProperties is changed: %s -> %s", baseLevel, headLevel)
+}
+
+var additionalLevels = map[string]int{"allowed": 0, "schema": 1, "forbidden": 2}
+
+func additionalLevel(x openapi3.AdditionalProperties) string {
+	switch {
+	case x.Has != nil && !*x.Has:
+		return "forbidden"
+	case x.Schema != nil:
+		return "schema"
+	default:
+		return "allowed"
+	}
+}
+
+// composition compares allOf/oneOf/anyOf by position. The value must match all of allOf (the added schema tightens),
+// and one (or any) of oneOf/anyOf (the added schema loosens).
+func (d *differ) composition(path, name string, base, head openapi3.SchemaRefs, all bool, dir direction) {
+	for i := 0; i < len(base) || i < len(head); i++ {
+		p := fmt.Sprintf("%s.%s[%d]", path, name, i)
+		switch {
+		case i >= len(head):
+			d.restrict(p, !all, dir, "%s schema is removed", name)
+		case i >= len(base):
+			d.restrict(p, all, dir, "%s schema is added", name)
+		default:
+			d.schema(p, base[i], head[i], dir)
+		}
+	}
+}
+
+func uint64ToFloat(x *uint64) *float64 {
+	if x == nil {
+		return nil
+	}
+	v := float64(*x)
+	return &v
+}
+
+// bound formats the minimum/maximum (e.g. 1, 1 (exclusive)).
+func bound(v float64, exclusive bool) string {
+	if exclusive {
+		return fmt.Sprintf("%v (exclusive)", v)
+	}
+	return fmt.Sprint(v)
+}
+
+func (d *differ) enum(path string, base, head []any, dir direction) {
+	if len(base) == 0 && len(head) == 0 {
+		return
+	}
+	if len(base) > 0 && len(head) == 0 { // the restriction is removed
+		if dir == response {
+			d.add(KindBreaking, path, "enum is removed")
+		} else {
+			d.add(KindNonBreaking, path, "enum is removed")
+		}
+		return
+	}
+	if len(base) == 0 { // the restriction is added
+		if dir == request {
+			d.add(KindBreaking, path, "enum is added")
+		} else {
+			d.add(KindNonBreaking, path, "enum is added")
+		}
+		return
+	}
+
+	baseValues := map[string]bool{}
+	for _, v := range base {
+		baseValues[fmt.Sprint(v)] = true
+	}
+	headValues := map[string]bool{}
+	for _, v := range head {
+		headValues[fmt.Sprint(v)] = true
+	}
+	for _, v := range sortedKeys(baseValues) {
+		if headValues[v] {
+			continue
+		}
+		if dir == request {
+			d.add(KindBreaking, path, "enum value %q is removed", v)
+		} else {
+			d.add(KindNonBreaking, path, "enum value %q is removed", v)
+		}
+	}
+	for _, v := range sortedKeys(headValues) {
+		if baseValues[v] {
+			continue
+		}
+		if dir == response {
+			d.add(KindBreaking, path, "enum value %q is added", v)
+		} else {
+			d.add(KindNonBreaking, path, "enum value %q is added", v)
+		}
+	}
+}
+
+// parameters returns the parameters keyed by "<in>.<name>" (e.g. query.limit).
+func parameters(op *openapi3.Operation) map[string]*openapi3.Parameter {
+	r := map[string]*openapi3.Parameter{}
+	for _, p := range op.Parameters {
+		if p.Value != nil {
+			r[p.Value.In+"."+p.Value.Name] = p.Value
+		}
+	}
+	return r
+}
+
+func requestBody(op *openapi3.Operation) *openapi3.RequestBody {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return op.RequestBody.Value
+}
+
+func set(xs []string) map[string]bool {
+	r := make(map[string]bool, len(xs))
+	for _, x := range xs {
+		r[x] = true
+	}
+	return r
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/go-cmp/cmp"
+)
+
+const baseDoc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "emoji API", "version": "0.0.0"},
+  "paths": {
+    "/emoji/suggest": {
+      "post": {
+        "operationId": "suggest",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {
+            "type": "object",
+            "properties": {
+              "prefix": {"type": "string"},
+              "sort": {"type": "string", "enum": ["asc", "desc"]},
+              "limit": {"type": "integer"}
+            },
+            "required": ["prefix", "sort"]
+          }}}
+        },
+        "responses": {
+          "200": {"description": "", "content": {"application/json": {"schema": {
+            "type": "array",
+            "items": {"$ref": "#/components/schemas/EmojiDefinition"}
+          }}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "EmojiDefinition": {
+        "type": "object",
+        "properties": {"alias": {"type": "string"}, "char": {"type": "string"}},
+        "required": ["alias", "char"]
+      }
+    }
+  }
+}`
+
+func TestDiff(t *testing.T) {
+	const (
+		schemes  = `"components": {"securitySchemes": {"bearerAuth": {"type": "http", "scheme": "bearer"}, "apiKeyAuth": {"type": "apiKey", "in": "header", "name": "X-API-Key"}},`
+		secured  = `"operationId": "suggest", "security": [{"bearerAuth": ["read"]}, {"apiKeyAuth": ["read"]}],`
+		replaced = `"operationId": "suggest",`
+	)
+	tests := []struct {
+		name        string
+		baseReplace []string // old, new pairs applied to baseDoc, for the base document
+		replace     []string // old, new pairs applied to baseDoc
+		want        []string
+	}{
+		{name: "same", want: []string{}},
+		{name: "narrowed-enum",
+			replace: []string{`"enum": ["asc", "desc"]`, `"enum": ["asc"]`},
+			want:    []string{`breaking POST /emoji/suggest requestBody.sort.enum: enum value "desc" is removed`},
+		},
+		{name: "widened-enum",
+			replace: []string{`"enum": ["asc", "desc"]`, `"enum": ["asc", "desc", "random"]`},
+			want:    []string{`non-breaking POST /emoji/suggest requestBody.sort.enum: enum value "random" is added`},
+		},
+		{name: "newly-required-request-property",
+			replace: []string{`"required": ["prefix", "sort"]`, `"required": ["prefix", "sort", "limit"]`},
+			want:    []string{`breaking POST /emoji/suggest requestBody.limit: property becomes required`},
+		},
+		{name: "removed-response-field",
+			replace: []string{`"properties": {"alias": {"type": "string"}, "char": {"type": "string"}},
+        "required": ["alias", "char"]`, `"properties": {"alias": {"type": "string"}},
+        "required": ["alias"]`},
+			want: []string{`breaking POST /emoji/suggest responses.200[].char: property is removed`},
+		},
+		{name: "added-response-field",
+			replace: []string{`"char": {"type": "string"}}`, `"char": {"type": "string"}, "description": {"type": "string"}}`},
+			want:    []string{`non-breaking POST /emoji/suggest responses.200[].description: property is added`},
+		},
+		{name: "type-changed",
+			replace: []string{`"limit": {"type": "integer"}`, `"limit": {"type": "string"}`},
+			want:    []string{`breaking POST /emoji/suggest requestBody.limit: type is changed: "integer" -> "string"`},
+		},
+		{name: "added-max-length",
+			replace: []string{`"prefix": {"type": "string"}`, `"prefix": {"type": "string", "maxLength": 10}`},
+			want:    []string{`breaking POST /emoji/suggest requestBody.prefix: maxLength is added: 10`},
+		},
+		{name: "loosened-max-length",
+			baseReplace: []string{`"prefix": {"type": "string"}`, `"prefix": {"type": "string", "maxLength": 10}`},
+			replace:     []string{`"prefix": {"type": "string"}`, `"prefix": {"type": "string", "maxLength": 20}`},
+			want:        []string{`non-breaking POST /emoji/suggest requestBody.prefix: maxLength is changed: 10 -> 20`},
+		},
+		{name: "added-max-length-in-response",
+			replace: []string{`"char": {"type": "string"}`, `"char": {"type": "string", "maxLength": 8}`},
+			want:    []string{`non-breaking POST /emoji/suggest responses.200[].char: maxLength is added: 8`},
+		},
+		{name: "added-range",
+			replace: []string{`"limit": {"type": "integer"}`, `"limit": {"type": "integer", "minimum": 1, "maximum": 100}`},
+			want: []string{
+				`breaking POST /emoji/suggest requestBody.limit: minimum is added: 1`,
+				`breaking POST /emoji/suggest requestBody.limit: maximum is added: 100`,
+			},
+		},
+		{name: "exclusive-maximum",
+			baseReplace: []string{`"limit": {"type": "integer"}`, `"limit": {"type": "integer", "maximum": 100}`},
+			replace:     []string{`"limit": {"type": "integer"}`, `"limit": {"type": "integer", "maximum": 100, "exclusiveMaximum": true}`},
+			want:        []string{`breaking POST /emoji/suggest requestBody.limit: maximum is changed: 100 -> 100 (exclusive)`},
+		},
+		{name: "removed-minimum",
+			baseReplace: []string{`"limit": {"type": "integer"}`, `"limit": {"type": "integer", "minimum": 1}`},
+			want:        []string{`non-breaking POST /emoji/suggest requestBody.limit: minimum is removed: 1`},
+		},
+		{name: "added-pattern",
+			replace: []string{`"prefix": {"type": "string"}`, `"prefix": {"type": "string", "pattern": "^:"}`},
+			want:    []string{`breaking POST /emoji/suggest requestBody.prefix: pattern is added: "^:"`},
+		},
+		{name: "changed-pattern",
+			baseReplace: []string{`"prefix": {"type": "string"}`, `"prefix": {"type": "string", "pattern": "^:"}`},
+			replace:     []string{`"prefix": {"type": "string"}`, `"prefix": {"type": "string", "pattern": "^:[a-z]"}`},
+			want:        []string{`breaking POST /emoji/suggest requestBody.prefix: pattern is changed: "^:" -> "^:[a-z]"`},
+		},
+		{name: "forbidden-additional-properties",
+			replace: []string{`"required": ["prefix", "sort"]`, `"required": ["prefix", "sort"], "additionalProperties": false`},
+			want:    []string{`breaking POST /emoji/suggest requestBody.additionalProperties: additionalProperties is changed: allowed -> forbidden`},
+		},
+		{name: "allowed-additional-properties",
+			baseReplace: []string{`"required": ["prefix", "sort"]`, `"required": ["prefix", "sort"], "additionalProperties": false`},
+			want:        []string{`non-breaking POST /emoji/suggest requestBody.additionalProperties: additionalProperties is changed: forbidden -> allowed`},
+		},
+		{name: "added-all-of",
+			baseReplace: []string{`"prefix": {"type": "string"}`, `"prefix": {"allOf": [{"type": "string"}]}`},
+			replace:     []string{`"prefix": {"type": "string"}`, `"prefix": {"allOf": [{"type": "string"}, {"maxLength": 10}]}`},
+			want:        []string{`breaking POST /emoji/suggest requestBody.prefix.allOf[1]: allOf schema is added`},
+		},
+		{name: "removed-one-of",
+			baseReplace: []string{`"limit": {"type": "integer"}`, `"limit": {"oneOf": [{"type": "integer"}, {"type": "string"}]}`},
+			replace:     []string{`"limit": {"type": "integer"}`, `"limit": {"oneOf": [{"type": "integer"}]}`},
+			want:        []string{`breaking POST /emoji/suggest requestBody.limit.oneOf[1]: oneOf schema is removed`},
+		},
+		{name: "added-any-of",
+			baseReplace: []string{`"limit": {"type": "integer"}`, `"limit": {"anyOf": [{"type": "integer"}]}`},
+			replace:     []string{`"limit": {"type": "integer"}`, `"limit": {"anyOf": [{"type": "integer"}, {"type": "string"}]}`},
+			want:        []string{`non-breaking POST /emoji/suggest requestBody.limit.anyOf[1]: anyOf schema is added`},
+		},
+		{name: "security-added",
+			replace: []string{`"components": {`, schemes, replaced, secured},
+			want:    []string{`breaking POST /emoji/suggest security: security requirement is added: bearerAuth[read] | apiKeyAuth[read]`},
+		},
+		{name: "security-removed",
+			baseReplace: []string{`"components": {`, schemes, replaced, secured},
+			want:        []string{`non-breaking POST /emoji/suggest security: security requirement is removed`},
+		},
+		{name: "security-scope-added",
+			baseReplace: []string{`"components": {`, schemes, replaced, secured},
+			replace:     []string{`"components": {`, schemes, replaced, `"operationId": "suggest", "security": [{"bearerAuth": ["read", "write"]}, {"apiKeyAuth": ["read"]}],`},
+			want: []string{
+				`breaking POST /emoji/suggest security: security alternative is removed: bearerAuth[read]`,
+				`non-breaking POST /emoji/suggest security: security alternative is added: bearerAuth[read,write]`,
+			},
+		},
+		{name: "required-scopes-changed",
+			baseReplace: []string{`"components": {`, schemes, replaced, `"operationId": "suggest", "security": [{"bearerAuth": []}], "x-required-scopes": ["read"],`},
+			replace:     []string{`"components": {`, schemes, replaced, `"operationId": "suggest", "security": [{"bearerAuth": []}], "x-required-scopes": ["read", "write"],`},
+			want: []string{
+				`breaking POST /emoji/suggest security: security alternative is removed: bearerAuth[read]`,
+				`non-breaking POST /emoji/suggest security: security alternative is added: bearerAuth[read,write]`,
+			},
+		},
+		{name: "removed-operation",
+			replace: []string{`"/emoji/suggest"`, `"/emoji/suggest2"`},
+			want: []string{
+				`breaking POST /emoji/suggest: operation is removed`,
+				`non-breaking POST /emoji/suggest2: operation is added`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := loadDoc(t, strings.NewReplacer(tt.baseReplace...).Replace(baseDoc))
+			head := loadDoc(t, strings.NewReplacer(tt.replace...).Replace(baseDoc))
+
+			report := Diff(base, head)
+			got := make([]string, len(report.Changes))
+			for i, c := range report.Changes {
+				got[i] = c.Kind + " " + c.Path + ": " + c.Message
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Diff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func loadDoc(t *testing.T, doc string) *openapi3.T {
+	t.Helper()
+	loader := openapi3.NewLoader()
+	r, err := loader.LoadFromData([]byte(doc))
+	if err != nil {
+		t.Fatalf("load: %+v", err)
+	}
+	if err := r.Validate(context.Background()); err != nil {
+		t.Fatalf("validate: %+v", err)
+	}
+	return r
+}
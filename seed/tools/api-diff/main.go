@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+)
+
+type Options struct {
+	Format      string // text or json
+	AllowBreaks bool   // exit with zero status even if the breaking changes are found
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("** ")
+
+	options := &Options{}
+	pflag.StringVar(&options.Format, "format", "text", "output format (text|json)")
+	pflag.BoolVar(&options.AllowBreaks, "allow-breaking", false, "exit with zero status, even if the breaking changes are found")
+	pflag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: api-diff [flags] <base> <head>")                                               // nolint
+		fmt.Fprintln(os.Stderr, "  <base>, <head> are the file path or git:<rev>:<path> (e.g. git:HEAD:openapi.json)") // nolint
+		pflag.PrintDefaults()
+	}
+	pflag.Parse()
+
+	if pflag.NArg() != 2 {
+		pflag.Usage()
+		os.Exit(2)
+	}
+	if options.Format != "text" && options.Format != "json" {
+		log.Fatalf("!! unexpected --format value: %q (text|json)", options.Format)
+	}
+
+	report, err := run(pflag.Arg(0), pflag.Arg(1))
+	if err != nil {
+		log.Fatalf("!! %+v", err)
+	}
+	if err := Write(os.Stdout, report, options.Format); err != nil {
+		log.Fatalf("!! %+v", err)
+	}
+	if report.HasBreakingChanges() && !options.AllowBreaks {
+		os.Exit(1)
+	}
+}
+
+func run(baseName, headName string) (*Report, error) {
+	base, err := load(baseName)
+	if err != nil {
+		return nil, fmt.Errorf("load base: %w", err)
+	}
+	head, err := load(headName)
+	if err != nil {
+		return nil, fmt.Errorf("load head: %w", err)
+	}
+	return Diff(base, head), nil
+}
+
+// load loads the openapi doc from the file, or from the git revision (git:<rev>:<path>).
+func load(name string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	if !strings.HasPrefix(name, "git:") {
+		return loader.LoadFromFile(name)
+	}
+
+	out, err := exec.Command("git", "show", strings.TrimPrefix(name, "git:")).Output()
+	if err != nil {
+		if err, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git show %s: %s", strings.TrimPrefix(name, "git:"), strings.TrimSpace(string(err.Stderr)))
+		}
+		return nil, fmt.Errorf("git show: %w", err)
+	}
+	return loader.LoadFromData(out)
+}
+
+// Write writes the report as text or json.
+func Write(w io.Writer, report *Report, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	for _, c := range report.Changes {
+		if _, err := fmt.Fprintf(w, "%-12s %s: %s\n", c.Kind, c.Path, c.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "breaking=%d non-breaking=%d\n", report.Breaking, report.NonBreaking)
+	return err
+}
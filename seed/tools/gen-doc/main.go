@@ -1,50 +1,180 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/iancoleman/orderedmap"
 	"github.com/podhmo/emoji-api/seed/design"
 	"github.com/podhmo/emoji-api/seed/design/action"
 	"github.com/podhmo/gos/openapigen"
 	"github.com/podhmo/gos/pkg/maplib"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
 )
 
+type Options struct {
+	Format     string // json or yaml
+	OpenAPI    string // 3.0.3 or 3.1.0
+	APIVersion string // the version of info.version (default: the latest git tag)
+	Config     string // the config file (servers, ...)
+	ActionDir  string // the source directory of the action package, for checking the unrouted actions
+}
+
+// Config is the content of --config file.
+//
+//	servers:
+//	  - url: http://localhost:8080
+//	    description: local development
+type Config struct {
+	Servers []struct {
+		URL         string `yaml:"url"`
+		Description string `yaml:"description"`
+	} `yaml:"servers"`
+}
+
 func main() {
+	log.SetFlags(0)
+	log.SetPrefix("** ")
+
+	options := &Options{}
+	pflag.StringVar(&options.Format, "format", "json", "output format (json|yaml)")
+	pflag.StringVar(&options.OpenAPI, "openapi", OpenAPI303, "the version of the openapi spec ("+OpenAPI303+"|"+OpenAPI310+")")
+	pflag.StringVar(&options.APIVersion, "api-version", "", "the version of the API (default: the latest git tag, or 0.0.0)")
+	pflag.StringVar(&options.Config, "config", "", "config file (e.g. ./seed/tools/doc-conf.yaml)")
+	pflag.StringVar(&options.ActionDir, "action-dir", "./seed/design/action", "the source directory of the action package, fails if the actions which are not routed are found (empty: skip the check)")
+	pflag.Parse()
+
+	if err := run(options, os.Stdout); err != nil {
+		log.Fatalf("!! %+v", err)
+	}
+}
+
+func run(options *Options, w io.Writer) error {
+	if options.Format != "json" && options.Format != "yaml" {
+		return fmt.Errorf("unexpected --format value: %q (json|yaml)", options.Format)
+	}
+	if options.OpenAPI != OpenAPI303 && options.OpenAPI != OpenAPI310 {
+		return fmt.Errorf("unexpected --openapi value: %q (%s|%s)", options.OpenAPI, OpenAPI303, OpenAPI310)
+	}
+
+	config := &Config{}
+	if options.Config != "" {
+		b, err := os.ReadFile(options.Config)
+		if err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+		if err := yaml.Unmarshal(b, config); err != nil {
+			return fmt.Errorf("load config %s: %w", options.Config, err)
+		}
+	}
+	servers := []openapigen.Server{
+		{
+			URL: "http://localhost:8080",
+			Doc: "local development",
+		},
+	}
+	if len(config.Servers) > 0 {
+		servers = make([]openapigen.Server, len(config.Servers))
+		for i, s := range config.Servers {
+			servers[i] = openapigen.Server{URL: s.URL, Doc: s.Description}
+		}
+	}
+
+	apiVersion := options.APIVersion
+	if apiVersion == "" {
+		apiVersion = versionFromGitTag()
+	}
+
+	if options.ActionDir != "" {
+		unrouted, err := action.Unrouted(options.ActionDir)
+		if err != nil {
+			return fmt.Errorf("check routes: %w", err)
+		}
+		if len(unrouted) > 0 {
+			return fmt.Errorf("the actions are defined but never routed:\n\t%s", strings.Join(unrouted, "\n\t"))
+		}
+	}
+
 	b := openapigen.NewBuilder(openapigen.DefaultConfig())
 
-	// routing
+	// routing (the routes are declared next to the actions, in seed/design/action)
 	r := openapigen.NewRouter(design.Error)
-	{
-		r := r.Tagged("emoji")
-		r.Post("/emoji/translate", action.EmojiTranslate)
-		r.Post("/emoji/suggest", action.EmojiSuggest)
+	if err := action.Register(r); err != nil {
+		return fmt.Errorf("routing: %w", err)
 	}
 
 	// openapi data
 	doc, err := maplib.Merge(orderedmap.New(), &openapigen.OpenAPI{
-		OpenAPI: "3.0.3",
+		OpenAPI: options.OpenAPI,
 		Info: openapigen.Info{
 			Title:   "emoji API",
-			Version: "0.0.0",
+			Version: strings.TrimPrefix(apiVersion, "v"),
 			Doc:     "emoji API",
 		},
-		Servers: []openapigen.Server{
-			{
-				URL: "http://localhost:8080",
-				Doc: "local development",
-			},
-		},
+		Servers: servers,
 	})
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("build openapi doc: %w", err)
+	}
+	if err := r.ToSchemaWith(b, doc); err != nil {
+		return fmt.Errorf("build openapi doc: %w", err)
 	}
 
-	r.ToSchemaWith(b, doc)
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(doc); err != nil {
-		panic(err)
+	// normalize (as orderedmap.OrderedMap and []interface{}), for the post-processing
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(doc); err != nil {
+		return fmt.Errorf("encode openapi doc: %w", err)
+	}
+	normalized := orderedmap.New()
+	if err := json.Unmarshal(buf.Bytes(), normalized); err != nil {
+		return fmt.Errorf("decode openapi doc: %w", err)
+	}
+	if err := AddSecurity(normalized, design.SecuritySchemes, action.Routes()); err != nil {
+		return fmt.Errorf("add security: %w", err)
+	}
+	if err := AddResponses(normalized, action.Routes()); err != nil {
+		return fmt.Errorf("add responses: %w", err)
+	}
+	if err := AddZeroValues(normalized, action.Routes()); err != nil {
+		return fmt.Errorf("add zero values: %w", err)
+	}
+	if options.OpenAPI == OpenAPI310 {
+		ConvertToOpenAPI31(normalized)
+	}
+
+	switch options.Format {
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(ToYAMLNode(normalized)); err != nil {
+			return fmt.Errorf("encode openapi doc as yaml: %w", err)
+		}
+		return enc.Close()
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(normalized); err != nil {
+			return fmt.Errorf("encode openapi doc as json: %w", err)
+		}
+		return nil
+	}
+}
+
+// versionFromGitTag returns the latest git tag (e.g. v0.1.0), or 0.0.0 if no tags are found.
+func versionFromGitTag() string {
+	out, err := exec.Command("git", "describe", "--tags", "--abbrev=0").Output()
+	if err != nil {
+		return "0.0.0"
+	}
+	if v := strings.TrimSpace(string(out)); v != "" {
+		return v
 	}
+	return "0.0.0"
 }
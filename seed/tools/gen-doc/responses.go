@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/podhmo/emoji-api/seed/design/action"
+)
+
+// AddResponses adds the representations which openapigen cannot declare, for the routes having Produces or Cached.
+//
+//   - Produces: the media types of the 200 response (the schema is string, the body is the text)
+//   - Cached: the If-None-Match parameter, the ETag header of the 200 response, and the 304 response
+func AddResponses(doc *orderedmap.OrderedMap, routes []*action.Route) error {
+	paths := childMap(doc, "paths")
+	for _, r := range routes {
+		if len(r.Produces) == 0 && !r.Cached {
+			continue
+		}
+		if _, ok := paths.Get(r.Path); !ok {
+			return fmt.Errorf("the path of the route is not found: %s %s", strings.ToUpper(r.Method), r.Path)
+		}
+		op := childMap(childMap(paths, r.Path), strings.ToLower(r.Method))
+		res200 := childMap(childMap(op, "responses"), "200")
+
+		if len(r.Produces) > 0 {
+			content := childMap(res200, "content")
+			for _, mediaType := range r.Produces {
+				v := orderedmap.New()
+				v.Set("schema", stringSchema())
+				content.Set(mediaType, v)
+			}
+		}
+
+		if r.Cached {
+			param := orderedmap.New()
+			param.Set("name", "If-None-Match")
+			param.Set("in", "header")
+			param.Set("description", "the ETag of the previous response, 304 if not modified")
+			param.Set("required", false)
+			param.Set("schema", stringSchema())
+			params, _ := op.Get("parameters")
+			list, _ := params.([]interface{})
+			op.Set("parameters", append(list, param))
+
+			childMap(res200, "headers").Set("ETag", etagHeader())
+
+			notModified := orderedmap.New()
+			notModified.Set("description", "not modified (If-None-Match is matched with the ETag)")
+			headers := orderedmap.New()
+			headers.Set("ETag", etagHeader())
+			notModified.Set("headers", headers)
+
+			responses := childMap(op, "responses")
+			sorted := orderedmap.New() // 200, 304, default
+			for _, k := range responses.Keys() {
+				if k == "default" {
+					sorted.Set("304", notModified)
+				}
+				v, _ := responses.Get(k)
+				sorted.Set(k, v)
+			}
+			if _, ok := sorted.Get("304"); !ok {
+				sorted.Set("304", notModified)
+			}
+			op.Set("responses", sorted)
+		}
+	}
+	return nil
+}
+
+func stringSchema() *orderedmap.OrderedMap {
+	schema := orderedmap.New()
+	schema.Set("type", "string")
+	return schema
+}
+
+func etagHeader() *orderedmap.OrderedMap {
+	header := orderedmap.New()
+	header.Set("description", "the ETag of the representation, for If-None-Match")
+	header.Set("schema", stringSchema())
+	return header
+}
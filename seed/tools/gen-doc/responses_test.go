@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/podhmo/emoji-api/seed/design/action"
+)
+
+func TestAddResponses(t *testing.T) {
+	const etag = `{"description":"the ETag of the representation, for If-None-Match","schema":{"type":"string"}}`
+	const ifNoneMatch = `{"name":"If-None-Match","in":"header","description":"the ETag of the previous response, 304 if not modified","required":false,"schema":{"type":"string"}}`
+
+	tests := []struct {
+		name    string
+		route   *action.Route
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "produces", route: &action.Route{Method: "get", Path: "/export", Produces: []string{"text/csv"}},
+			input: `{"paths": {"/export": {"get": {"responses": {"200": {"content": {"application/json": {}}}, "default": {}}}}}}`,
+			want:  `{"paths":{"/export":{"get":{"responses":{"200":{"content":{"application/json":{},"text/csv":{"schema":{"type":"string"}}}},"default":{}}}}}}`,
+		},
+		{name: "cached", route: &action.Route{Method: "get", Path: "/export", Cached: true},
+			input: `{"paths": {"/export": {"get": {"parameters": [{"name": "format"}], "responses": {"200": {}, "default": {}}}}}}`,
+			want:  `{"paths":{"/export":{"get":{"parameters":[{"name":"format"},` + ifNoneMatch + `],"responses":{"200":{"headers":{"ETag":` + etag + `}},"304":{"description":"not modified (If-None-Match is matched with the ETag)","headers":{"ETag":` + etag + `}},"default":{}}}}}}`,
+		},
+		{name: "cached-without-params", route: &action.Route{Method: "get", Path: "/export", Cached: true},
+			input: `{"paths": {"/export": {"get": {"responses": {"200": {}}}}}}`,
+			want:  `{"paths":{"/export":{"get":{"responses":{"200":{"headers":{"ETag":` + etag + `}},"304":{"description":"not modified (If-None-Match is matched with the ETag)","headers":{"ETag":` + etag + `}}},"parameters":[` + ifNoneMatch + `]}}}}`,
+		},
+		{name: "nothing", route: &action.Route{Method: "get", Path: "/healthz"},
+			input: `{"paths": {}}`,
+			want:  `{"paths":{}}`,
+		},
+		{name: "unknown-path", route: &action.Route{Method: "get", Path: "/export", Cached: true},
+			input:   `{"paths": {}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := orderedmap.New()
+			if err := json.Unmarshal([]byte(tt.input), doc); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			err := AddResponses(doc, []*action.Route{tt.route})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("want error, but nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("AddResponses() mismatch\nwant: %s\ngot:  %s", tt.want, got)
+			}
+		})
+	}
+}
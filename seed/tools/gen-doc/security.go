@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/podhmo/emoji-api/seed/design"
+	"github.com/podhmo/emoji-api/seed/design/action"
+)
+
+// AddSecurity adds components.securitySchemes, and the security requirements of the routes having the scopes.
+// The operation accepts one of the schemes (e.g. [{"bearerAuth": []}, {"apiKeyAuth": []}]).
+// The scope list of http/apiKey schemes must be empty in OAS 3.0 (scopes are for oauth2/openIdConnect),
+// then the scopes are in x-required-scopes of the operation (read by middleware.Auth).
+func AddSecurity(doc *orderedmap.OrderedMap, schemes []design.SecurityScheme, routes []*action.Route) error {
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	components := childMap(doc, "components")
+	securitySchemes := orderedmap.New()
+	for _, s := range schemes {
+		v := orderedmap.New()
+		v.Set("type", s.Type)
+		if s.Doc != "" {
+			v.Set("description", s.Doc)
+		}
+		switch s.Type {
+		case "http":
+			v.Set("scheme", s.Scheme)
+		case "apiKey":
+			v.Set("in", s.In)
+			v.Set("name", s.Param)
+		default:
+			return fmt.Errorf("unexpected type of the security scheme %s: %q (http|apiKey)", s.Name, s.Type)
+		}
+		securitySchemes.Set(s.Name, v)
+	}
+	components.Set("securitySchemes", securitySchemes)
+
+	paths := childMap(doc, "paths")
+	for _, r := range routes {
+		if r.Scopes == nil {
+			continue
+		}
+		if _, ok := paths.Get(r.Path); !ok {
+			return fmt.Errorf("the path of the route is not found: %s %s", strings.ToUpper(r.Method), r.Path)
+		}
+		op := childMap(childMap(paths, r.Path), strings.ToLower(r.Method))
+
+		security := make([]interface{}, len(schemes))
+		for i, s := range schemes {
+			requirement := orderedmap.New()
+			requirement.Set(s.Name, []string{})
+			security[i] = requirement
+		}
+		op.Set("security", security)
+		op.Set(RequiredScopesExtension, append([]string{}, r.Scopes...))
+	}
+	return nil
+}
+
+// RequiredScopesExtension is the extension of the operation, having the scopes of the API key.
+const RequiredScopesExtension = "x-required-scopes"
+
+// childMap returns the map of the key (created if not found), as the pointer stored in the parent.
+func childMap(parent *orderedmap.OrderedMap, key string) *orderedmap.OrderedMap {
+	switch v, _ := parent.Get(key); v := v.(type) {
+	case *orderedmap.OrderedMap:
+		return v
+	case orderedmap.OrderedMap:
+		parent.Set(key, &v)
+		return &v
+	default:
+		m := orderedmap.New()
+		parent.Set(key, m)
+		return m
+	}
+}
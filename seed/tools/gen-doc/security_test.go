@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/podhmo/emoji-api/seed/design"
+	"github.com/podhmo/emoji-api/seed/design/action"
+)
+
+func TestAddSecurity(t *testing.T) {
+	schemes := []design.SecurityScheme{
+		{Name: "bearerAuth", Type: "http", Scheme: "bearer"},
+		{Name: "apiKeyAuth", Type: "apiKey", In: "header", Param: "X-API-Key"},
+	}
+	routes := []*action.Route{
+		{Method: "post", Path: "/emoji/suggest", Scopes: []string{"read"}},
+		{Method: "get", Path: "/healthz"},
+	}
+
+	tests := []struct {
+		name    string
+		schemes []design.SecurityScheme
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "ok", schemes: schemes,
+			input: `{"paths": {"/emoji/suggest": {"post": {"operationId": "suggest"}}, "/healthz": {"get": {"operationId": "healthz"}}}, "components": {"schemas": {}}}`,
+			want:  `{"paths":{"/emoji/suggest":{"post":{"operationId":"suggest","security":[{"bearerAuth":[]},{"apiKeyAuth":[]}],"x-required-scopes":["read"]}},"/healthz":{"get":{"operationId":"healthz"}}},"components":{"schemas":{},"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer"},"apiKeyAuth":{"type":"apiKey","in":"header","name":"X-API-Key"}}}}`,
+		},
+		{name: "no-schemes",
+			input: `{"paths": {}}`,
+			want:  `{"paths":{}}`,
+		},
+		{name: "unknown-path", schemes: schemes,
+			input:   `{"paths": {"/healthz": {"get": {"operationId": "healthz"}}}}`,
+			wantErr: true,
+		},
+		{name: "unexpected-type", schemes: []design.SecurityScheme{{Name: "oauth", Type: "oauth2"}},
+			input:   `{"paths": {"/emoji/suggest": {"post": {}}, "/healthz": {"get": {}}}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := orderedmap.New()
+			if err := json.Unmarshal([]byte(tt.input), doc); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			err := AddSecurity(doc, tt.schemes, routes)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("want error, but nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("AddSecurity() mismatch\nwant: %s\ngot:  %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/iancoleman/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OpenAPI303 = "3.0.3"
+	OpenAPI310 = "3.1.0"
+)
+
+// ConvertToOpenAPI31 rewrites the schemas in the OAS3.0 doc for OAS3.1 (JSON Schema 2020-12).
+//
+//   - {"type": "string", "nullable": true} -> {"type": ["string", "null"]}
+//   - {"example": ":dizzy:"} -> {"examples": [":dizzy:"]}
+//
+// The example of the parameter object and the media type object are kept as is (they are still valid in OAS3.1).
+func ConvertToOpenAPI31(doc *orderedmap.OrderedMap) {
+	walkDoc(doc)
+}
+
+func walkDoc(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *orderedmap.OrderedMap:
+		for _, k := range v.Keys() {
+			val, _ := v.Get(k)
+			v.Set(k, walkDocValue(k, val))
+		}
+		return v
+	case orderedmap.OrderedMap:
+		return *walkDoc(&v).(*orderedmap.OrderedMap)
+	case []interface{}:
+		for i, x := range v {
+			v[i] = walkDoc(x)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func walkDocValue(key string, v interface{}) interface{} {
+	switch key {
+	case "schema":
+		return convertSchema(v)
+	case "schemas": // components.schemas
+		return mapValues(v, convertSchema)
+	default:
+		return walkDoc(v)
+	}
+}
+
+func convertSchema(v interface{}) interface{} {
+	m, ok := asMap(v)
+	if !ok {
+		return v
+	}
+
+	for _, k := range m.Keys() {
+		val, _ := m.Get(k)
+		switch k {
+		case "properties", "patternProperties":
+			m.Set(k, mapValues(val, convertSchema))
+		case "items", "additionalProperties", "not":
+			m.Set(k, convertSchema(val))
+		case "allOf", "oneOf", "anyOf":
+			if xs, ok := val.([]interface{}); ok {
+				for i, x := range xs {
+					xs[i] = convertSchema(x)
+				}
+			}
+		}
+	}
+
+	r := orderedmap.New()
+	nullable := false
+	for _, k := range m.Keys() {
+		val, _ := m.Get(k)
+		switch k {
+		case "nullable":
+			nullable, _ = val.(bool)
+		case "example":
+			r.Set("examples", []interface{}{val})
+		default:
+			r.Set(k, val)
+		}
+	}
+	if nullable {
+		if typ, ok := r.Get("type"); ok {
+			if typ, ok := typ.(string); ok {
+				r.Set("type", []interface{}{typ, "null"})
+			}
+		} else { // e.g. {"$ref": "#/components/schemas/EmojiDefinition", "nullable": true}
+			wrapped := orderedmap.New()
+			wrapped.Set("anyOf", []interface{}{*r, map[string]interface{}{"type": "null"}})
+			r = wrapped
+		}
+	}
+	if _, isPtr := v.(*orderedmap.OrderedMap); isPtr {
+		return r
+	}
+	return *r
+}
+
+func mapValues(v interface{}, fn func(interface{}) interface{}) interface{} {
+	m, ok := asMap(v)
+	if !ok {
+		return v
+	}
+	for _, k := range m.Keys() {
+		val, _ := m.Get(k)
+		m.Set(k, fn(val))
+	}
+	return v
+}
+
+func asMap(v interface{}) (*orderedmap.OrderedMap, bool) {
+	switch v := v.(type) {
+	case *orderedmap.OrderedMap:
+		return v, true
+	case orderedmap.OrderedMap:
+		return &v, true
+	default:
+		return nil, false
+	}
+}
+
+// ToYAMLNode converts the doc to yaml.Node, keeping the order of keys.
+func ToYAMLNode(v interface{}) *yaml.Node {
+	if m, ok := asMap(v); ok {
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range m.Keys() {
+			val, _ := m.Get(k)
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, ToYAMLNode(val))
+		}
+		return node
+	}
+	switch v := v.(type) {
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, x := range v {
+			node.Content = append(node.Content, ToYAMLNode(x))
+		}
+		return node
+	case map[string]interface{}:
+		m := orderedmap.New()
+		for _, k := range sortedKeys(v) {
+			m.Set(k, v[k])
+		}
+		return ToYAMLNode(m)
+	case float64:
+		if v == float64(int64(v)) {
+			return ToYAMLNode(int64(v))
+		}
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(v); err != nil {
+		panic(err) // scalar values decoded from JSON are always encodable
+	}
+	return node
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
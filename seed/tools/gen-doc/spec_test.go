@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+func TestConvertToOpenAPI31(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "nullable",
+			input: `{"components": {"schemas": {"S": {"type": "object", "properties": {"x": {"type": "string", "nullable": true}}}}}}`,
+			want:  `{"components":{"schemas":{"S":{"type":"object","properties":{"x":{"type":["string","null"]}}}}}}`,
+		},
+		{name: "nullable-ref",
+			input: `{"components": {"schemas": {"S": {"type": "object", "properties": {"x": {"$ref": "#/components/schemas/T", "nullable": true}}}}}}`,
+			want:  `{"components":{"schemas":{"S":{"type":"object","properties":{"x":{"anyOf":[{"$ref":"#/components/schemas/T"},{"type":"null"}]}}}}}}`,
+		},
+		{name: "example",
+			input: `{"components": {"schemas": {"S": {"type": "array", "items": {"type": "string", "example": ":dizzy:", "description": "alias"}}}}}`,
+			want:  `{"components":{"schemas":{"S":{"type":"array","items":{"type":"string","examples":[":dizzy:"],"description":"alias"}}}}}`,
+		},
+		{name: "example-of-parameter-is-kept",
+			input: `{"paths": {"/x": {"get": {"parameters": [{"name": "q", "in": "query", "example": "foo", "schema": {"type": "string", "example": "bar"}}]}}}}`,
+			want:  `{"paths":{"/x":{"get":{"parameters":[{"name":"q","in":"query","example":"foo","schema":{"type":"string","examples":["bar"]}}]}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := orderedmap.New()
+			if err := json.Unmarshal([]byte(tt.input), doc); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			ConvertToOpenAPI31(doc)
+
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if want, got := tt.want, string(b); want != got {
+				t.Errorf("ConvertToOpenAPI31() mismatch\nwant: %s\n got: %s", want, got)
+			}
+		})
+	}
+}
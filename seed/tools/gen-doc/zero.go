@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/podhmo/emoji-api/seed/design"
+	"github.com/podhmo/emoji-api/seed/design/action"
+	"github.com/podhmo/gos/openapigen"
+)
+
+// AddZeroValues adds the zero-valued keywords (e.g. minimum: 0) which openapigen omits, to the schemas of the parameters
+// and the fields of the request body marked by design.Zero.
+func AddZeroValues(doc *orderedmap.OrderedMap, routes []*action.Route) error {
+	paths := childMap(doc, "paths")
+	for _, r := range routes {
+		input := r.Action.GetMetadata().Input
+		if input == nil {
+			continue
+		}
+		if _, ok := paths.Get(r.Path); !ok {
+			return fmt.Errorf("the path of the route is not found: %s %s", strings.ToUpper(r.Method), r.Path)
+		}
+		op := childMap(childMap(paths, r.Path), strings.ToLower(r.Method))
+
+		for _, p := range input.GetMetadata().Params {
+			keywords := design.ZeroKeywords(p.GetMetadata().Typ)
+			if len(keywords) == 0 {
+				continue
+			}
+			param := findParam(op, p.GetMetadata().Name)
+			if param == nil {
+				return fmt.Errorf("the parameter is not found: %s %s %s", strings.ToUpper(r.Method), r.Path, p.GetMetadata().Name)
+			}
+			setZero(childMap(param, "schema"), keywords)
+		}
+
+		if input.GetMetadata().Body == nil {
+			continue
+		}
+		object, ok := input.GetMetadata().Body.GetMetadata().Typ.(*openapigen.Object)
+		if !ok {
+			continue
+		}
+		for _, f := range object.GetMetadata().Fields {
+			keywords := design.ZeroKeywords(f.GetMetadata().Typ)
+			if len(keywords) == 0 {
+				continue
+			}
+			schema := childMap(childMap(childMap(childMap(op, "requestBody"), "content"), "application/json"), "schema")
+			if _, ok := schema.Get("$ref"); ok {
+				return fmt.Errorf("the request body is a reference, unsupported: %s %s", strings.ToUpper(r.Method), r.Path)
+			}
+			setZero(childMap(childMap(schema, "properties"), f.GetMetadata().Name), keywords)
+		}
+	}
+	return nil
+}
+
+// findParam returns the parameter of the operation (stored back as the pointer), nil if not found.
+func findParam(op *orderedmap.OrderedMap, name string) *orderedmap.OrderedMap {
+	params, _ := op.Get("parameters")
+	list, _ := params.([]interface{})
+	for i, v := range list {
+		var param *orderedmap.OrderedMap
+		switch v := v.(type) {
+		case *orderedmap.OrderedMap:
+			param = v
+		case orderedmap.OrderedMap:
+			param = &v
+			list[i] = param
+		default:
+			continue
+		}
+		if n, _ := param.Get("name"); n == name {
+			return param
+		}
+	}
+	return nil
+}
+
+// setZero sets the keywords in the order of openapigen.IntMetadata (default, maximum, minimum).
+func setZero(schema *orderedmap.OrderedMap, keywords []string) {
+	for _, k := range []string{"default", "maximum", "minimum"} {
+		for _, x := range keywords {
+			if x == k {
+				schema.Set(k, 0)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/podhmo/emoji-api/seed/design"
+	"github.com/podhmo/emoji-api/seed/design/action"
+)
+
+func TestAddZeroValues(t *testing.T) {
+	b := design.Builder
+
+	tests := []struct {
+		name    string
+		route   *action.Route
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "param", route: &action.Route{Method: "get", Path: "/emoji", Action: b.Action("list", b.Input(
+			b.Param("offset", design.Zero(b.Int().Minimum(0).Default(0), "minimum", "default")).Required(false),
+			b.Param("limit", b.Int().Minimum(1)).Required(false),
+		))},
+			input: `{"paths": {"/emoji": {"get": {"parameters": [{"name": "offset", "schema": {"type": "integer"}}, {"name": "limit", "schema": {"type": "integer", "minimum": 1}}]}}}}`,
+			want:  `{"paths":{"/emoji":{"get":{"parameters":[{"name":"offset","schema":{"type":"integer","default":0,"minimum":0}},{"name":"limit","schema":{"type":"integer","minimum":1}}]}}}}`,
+		},
+		{name: "body", route: &action.Route{Method: "post", Path: "/emoji/suggest", Action: b.Action("suggest", b.Input(b.Body(b.Object(
+			b.Field("prefix", b.String()),
+			b.Field("limit", design.Zero(b.Int().Minimum(0), "minimum")).Required(false),
+		))))},
+			input: `{"paths": {"/emoji/suggest": {"post": {"requestBody": {"content": {"application/json": {"schema": {"type": "object", "properties": {"prefix": {"type": "string"}, "limit": {"type": "integer"}}}}}}}}}}`,
+			want:  `{"paths":{"/emoji/suggest":{"post":{"requestBody":{"content":{"application/json":{"schema":{"type":"object","properties":{"prefix":{"type":"string"},"limit":{"type":"integer","minimum":0}}}}}}}}}}`,
+		},
+		{name: "nothing", route: &action.Route{Method: "get", Path: "/healthz", Action: b.Action("healthz")},
+			input: `{"paths": {}}`,
+			want:  `{"paths":{}}`,
+		},
+		{name: "unknown-param", route: &action.Route{Method: "get", Path: "/emoji", Action: b.Action("list", b.Input(
+			b.Param("offset", design.Zero(b.Int(), "minimum")),
+		))},
+			input:   `{"paths": {"/emoji": {"get": {}}}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := orderedmap.New()
+			if err := json.Unmarshal([]byte(tt.input), doc); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			err := AddZeroValues(doc, []*action.Route{tt.route})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("want error, but nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("AddZeroValues() mismatch\nwant: %s\ngot:  %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// constructor is the existing NewXXXController() function in dst dir.
+type constructor struct {
+	params []*dependency
+}
+
+// dependency is a parameter of the constructor, passed via the field of Dependencies.
+type dependency struct {
+	field    string // the field name of Dependencies (e.g. Catalog)
+	typ      string // e.g. *emojilib.Catalog
+	variadic bool
+	imports  []importSpec
+}
+
+type importSpec struct {
+	name string // "" if the name is same as the package name
+	path string
+}
+
+// ScanConstructor extracts the parameters of NewXXXController(), as dependencies.
+//
+//	func NewEmojiController(catalog *emojilib.Catalog, logger *slog.Logger) *EmojiController
+func ScanConstructor(fset *token.FileSet, f *ast.File, decl *ast.FuncDecl) (*constructor, error) {
+	imports := map[string]importSpec{} // package name -> import spec
+	for _, im := range f.Imports {
+		path, err := strconv.Unquote(im.Path.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: unexpected import: %w", fset.Position(im.Pos()), err)
+		}
+		name := PackageName(path)
+		spec := importSpec{path: path}
+		if im.Name != nil {
+			name = im.Name.Name
+			spec.name = im.Name.Name
+		}
+		imports[name] = spec
+	}
+
+	c := &constructor{}
+	buf := new(bytes.Buffer)
+	for i, p := range decl.Type.Params.List {
+		typ := p.Type
+		variadic := false
+		if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+			typ = ellipsis.Elt
+			variadic = true
+		}
+
+		buf.Reset()
+		if err := printer.Fprint(buf, fset, typ); err != nil {
+			return nil, fmt.Errorf("%s: print parameter type: %w", fset.Position(p.Pos()), err)
+		}
+		typeString := buf.String()
+		if variadic {
+			typeString = "[]" + typeString
+		}
+
+		var specs []importSpec
+		for _, name := range PackageNamesInUse(typ) {
+			if spec, ok := imports[name]; ok {
+				specs = append(specs, spec)
+			}
+		}
+
+		names := p.Names
+		if len(names) == 0 { // unnamed parameter, e.g. func NewEmojiController(*emojilib.Catalog)
+			names = []*ast.Ident{{Name: fieldNameFromType(typ, i)}}
+		}
+		for _, name := range names {
+			c.params = append(c.params, &dependency{field: ToTitle(name.Name), typ: typeString, variadic: variadic, imports: specs})
+		}
+	}
+	return c, nil
+}
+
+func fieldNameFromType(typ ast.Expr, i int) string {
+	switch typ := typ.(type) {
+	case *ast.Ident:
+		return typ.Name
+	case *ast.SelectorExpr:
+		return typ.Sel.Name
+	case *ast.StarExpr:
+		return fieldNameFromType(typ.X, i)
+	case *ast.ArrayType:
+		return fieldNameFromType(typ.Elt, i) + "List"
+	default:
+		return fmt.Sprintf("Arg%d", i)
+	}
+}
+
+// Dependencies is the aggregation of the constructor parameters of the controllers in a package.
+//
+//	type Dependencies struct {
+//		Catalog *emojilib.Catalog
+//	}
+type Dependencies struct {
+	fields  []*dependency
+	args    map[string][]string // controller type name -> arguments (e.g. deps.Catalog)
+	imports map[importSpec]bool
+}
+
+func NewDependencies() *Dependencies {
+	return &Dependencies{args: map[string][]string{}, imports: map[importSpec]bool{}}
+}
+
+// Add adds the parameters of the constructor of the controller.
+// The parameters having the same name and the same type are shared, if the types are conflicted, the field name is prefixed by the controller name.
+func (d *Dependencies) Add(typeName string, ctor *constructor) {
+	if ctor == nil {
+		return
+	}
+	for _, p := range ctor.params {
+		field := p.field
+		if prev := d.lookup(field); prev != nil && (prev.typ != p.typ || prev.variadic != p.variadic) {
+			field = strings.TrimSuffix(typeName, "Controller") + field // e.g. Logger -> EmojiLogger
+		}
+		if d.lookup(field) == nil {
+			d.fields = append(d.fields, &dependency{field: field, typ: p.typ, variadic: p.variadic, imports: p.imports})
+			for _, spec := range p.imports {
+				d.imports[spec] = true
+			}
+		}
+
+		arg := "deps." + field
+		if p.variadic {
+			arg += "..."
+		}
+		d.args[typeName] = append(d.args[typeName], arg)
+	}
+}
+
+// AddPackage adds the dependencies of the other package (used when the controllers are placed in the sub packages by x-go-package).
+func (d *Dependencies) AddPackage(typeName string, pkgname string, path string) {
+	field := ToTitle(pkgname)
+	spec := importSpec{name: pkgname, path: path}
+	d.fields = append(d.fields, &dependency{field: field, typ: pkgname + ".Dependencies", imports: []importSpec{spec}})
+	d.imports[spec] = true
+	d.args[typeName] = append(d.args[typeName], "deps."+field)
+}
+
+func (d *Dependencies) lookup(field string) *dependency {
+	for _, x := range d.fields {
+		if x.field == field {
+			return x
+		}
+	}
+	return nil
+}
+
+// Args returns the arguments of the constructor call (e.g. "deps.Catalog, deps.Logger").
+func (d *Dependencies) Args(typeName string) string {
+	return strings.Join(d.args[typeName], ", ")
+}
+
+// WriteImports writes the import specs used by the fields (without "import (" and ")").
+func (d *Dependencies) WriteImports(w io.Writer) {
+	specs := make([]importSpec, 0, len(d.imports))
+	for spec := range d.imports {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].path < specs[j].path })
+	for _, spec := range specs {
+		if spec.name == "" {
+			fmt.Fprintf(w, "\t%q\n", spec.path) // nolint
+		} else {
+			fmt.Fprintf(w, "\t%s %q\n", spec.name, spec.path) // nolint
+		}
+	}
+}
+
+// WriteStruct writes the definition of Dependencies.
+func (d *Dependencies) WriteStruct(w io.Writer) {
+	fmt.Fprintln(w, "// Dependencies :") // nolint
+	if len(d.fields) == 0 {
+		fmt.Fprintln(w, "type Dependencies struct{}") // nolint
+		fmt.Fprintln(w, "")                           // nolint
+		return
+	}
+	fmt.Fprintln(w, "type Dependencies struct {") // nolint
+	for _, x := range d.fields {
+		fmt.Fprintf(w, "\t%s %s\n", x.field, x.typ) // nolint
+	}
+	fmt.Fprintln(w, "}") // nolint
+	fmt.Fprintln(w, "")  // nolint
+}
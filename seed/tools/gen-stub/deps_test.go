@@ -0,0 +1,72 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDependencies(t *testing.T) {
+	source := `package api
+
+import (
+	"log/slog"
+
+	"github.com/podhmo/emoji-api/emojilib"
+)
+
+func NewEmojiController(catalog *emojilib.Catalog, logger *slog.Logger) *EmojiController { return nil }
+func NewAdminController(logger *slog.Logger, opts ...string) *AdminController { return nil }
+func NewAuditController(logger Logger) *AuditController { return nil }
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "api.go", source, parser.AllErrors)
+	if err != nil {
+		t.Fatalf("unexpected error (parse): %+v", err)
+	}
+
+	deps := NewDependencies()
+	for _, decl := range f.Decls {
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		ctor, err := ScanConstructor(fset, f, decl)
+		if err != nil {
+			t.Fatalf("unexpected error (ScanConstructor): %+v", err)
+		}
+		deps.Add(decl.Name.Name[len("New"):], ctor)
+	}
+
+	type field struct{ Name, Type string }
+	var gotFields []field
+	for _, x := range deps.fields {
+		gotFields = append(gotFields, field{Name: x.field, Type: x.typ})
+	}
+	wantFields := []field{
+		{Name: "Catalog", Type: "*emojilib.Catalog"},
+		{Name: "Logger", Type: "*slog.Logger"},
+		{Name: "Opts", Type: "[]string"},
+		{Name: "AuditLogger", Type: "Logger"}, // conflicted
+	}
+	if diff := cmp.Diff(wantFields, gotFields); diff != "" {
+		t.Errorf("fields mismatch (-want +got):\n%s", diff)
+	}
+
+	wantArgs := map[string]string{
+		"EmojiController": "deps.Catalog, deps.Logger",
+		"AdminController": "deps.Logger, deps.Opts...",
+		"AuditController": "deps.AuditLogger",
+	}
+	for typeName, want := range wantArgs {
+		if got := deps.Args(typeName); got != want {
+			t.Errorf("Args(%q) = %q, want %q", typeName, got, want)
+		}
+	}
+	if want, got := 2, len(deps.imports); want != got {
+		t.Errorf("imports: want=%d, but got=%d", want, got)
+	}
+}
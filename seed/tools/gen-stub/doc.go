@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DocComments returns the generated doc comment lines of the method, built from the openapi operation.
+// The hints for parameters and request body depend on the target (the example is for --target=strict).
+//
+//	// Suggest is endpoint of POST /emoji/suggest
+//	// <summary or description>
+//	//
+//	// * body   :requestBody                         -- "request.Body *oapigen.SuggestJSONRequestBody"
+//	//
+//	// * 200    :oapigen.Suggest200JSONResponse      -- ""
+//	// * default:oapigen.SuggestdefaultJSONResponse  -- "default error"
+func DocComments(name string, def *def, target *Target) []string {
+	var comments []string
+	comments = append(comments, fmt.Sprintf("%s is endpoint of %s %s", name, def.method, def.path))
+	doc := def.op.Summary
+	if doc == "" {
+		doc = def.op.Description
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			comments = append(comments, line)
+		}
+	}
+
+	// parametersの情報を追加 (どこに値が格納されるか)
+	// e.g.) * query :limit default=nil -- "max size" (request.Params.Limit)
+	if len(def.op.Parameters) > 0 || def.op.RequestBody != nil {
+		comments = append(comments, "")
+		for _, p := range def.op.Parameters {
+			if p.Value == nil {
+				continue
+			}
+			pname := p.Value.Name
+			if p.Value.Schema != nil && p.Value.Schema.Value != nil && p.Value.Schema.Value.Default != nil {
+				defaultValue := p.Value.Schema.Value.Default
+				if v, ok := defaultValue.(string); ok {
+					pname = fmt.Sprintf("%s default=%q", pname, v)
+				} else {
+					pname = fmt.Sprintf("%s default=%v", pname, defaultValue)
+				}
+			} else if !p.Value.Required {
+				pname = fmt.Sprintf("%s default=nil", pname)
+			}
+			field := target.paramsHint + "." + ToGoName(p.Value.Name)
+			if p.Value.In == openapi3.ParameterInPath {
+				field = ToGoName(p.Value.Name)
+				if target.Strict {
+					field = "request." + field
+				} else { // passed as argument
+					field = strings.ToLower(field[:1]) + field[1:]
+				}
+			}
+			comments = append(comments, fmt.Sprintf("* %-7s:%-35s -- %q (%s)", p.Value.In, pname, p.Value.Description, field))
+		}
+		if def.op.RequestBody != nil {
+			comments = append(comments, fmt.Sprintf("* %-7s:%-35s -- %q", "body", "requestBody", target.bodyHint(name, requestBodyType(name, def.op.RequestBody))))
+		}
+	}
+
+	// responsesの情報を追加
+	// e.g.) * 200   :oapigen.Suggest200JSONResponse -- ""
+	if len(def.op.Responses) > 0 {
+		comments = append(comments, "")
+		codes := make([]string, 0, len(def.op.Responses))
+		for code := range def.op.Responses {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes) // "default" is placed at the last
+		for _, code := range codes {
+			ref := def.op.Responses[code]
+			description := ""
+			if ref.Value != nil && ref.Value.Description != nil {
+				description = *ref.Value.Description
+			}
+			typ := responseContentType(ref)
+			if target.Strict {
+				typ = responseType(name, code, ref)
+			}
+			comments = append(comments, fmt.Sprintf("* %-7s:%-35s -- %q", code, typ, description))
+		}
+	}
+	return comments
+}
+
+// MergeDoc replaces the generated part of the existing doc comment with the generated lines.
+// The hand-written paragraphs after the generated part are kept as is.
+func MergeDoc(existing string, generated []string) []string {
+	existing = strings.TrimSpace(existing)
+	if existing == "" {
+		return generated
+	}
+
+	paragraphs := strings.Split(existing, "\n\n")
+	// the generated part is the header paragraph (<Name> is endpoint of ...),
+	// and the following paragraphs consisting of "* " lines only.
+	if !strings.Contains(paragraphs[0], " is endpoint of ") {
+		return append(append(generated, ""), strings.Split(existing, "\n")...)
+	}
+	rest := paragraphs[1:]
+	for len(rest) > 0 && isHintParagraph(rest[0]) {
+		rest = rest[1:]
+	}
+
+	r := generated
+	for _, p := range rest {
+		if p == deprecatedMarker { // the operation is defined again
+			continue
+		}
+		r = append(r, "")
+		r = append(r, strings.Split(p, "\n")...)
+	}
+	return r
+}
+
+func isHintParagraph(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !strings.HasPrefix(line, "* ") {
+			return false
+		}
+	}
+	return true
+}
+
+func requestBodyType(name string, ref *openapi3.RequestBodyRef) string {
+	if ref.Value == nil {
+		return "io.Reader"
+	}
+	for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded", "multipart/form-data", "text/plain"} {
+		if ref.Value.Content.Get(contentType) == nil {
+			continue
+		}
+		switch contentType {
+		case "application/json":
+			return fmt.Sprintf("*oapigen.%sJSONRequestBody", name)
+		case "application/x-www-form-urlencoded":
+			return fmt.Sprintf("*oapigen.%sFormdataRequestBody", name)
+		case "multipart/form-data":
+			return "*multipart.Reader"
+		case "text/plain":
+			return fmt.Sprintf("*oapigen.%sTextRequestBody", name)
+		}
+	}
+	return "io.Reader"
+}
+
+func responseContentType(ref *openapi3.ResponseRef) string {
+	if ref.Value == nil || len(ref.Value.Content) == 0 {
+		return "(no content)"
+	}
+	return strings.Join(sortedKeys(ref.Value.Content), ", ")
+}
+
+func responseType(name string, code string, ref *openapi3.ResponseRef) string {
+	if ref.Value != nil {
+		if ref.Value.Content.Get("application/json") != nil {
+			return fmt.Sprintf("oapigen.%s%sJSONResponse", name, code)
+		}
+		if ref.Value.Content.Get("text/plain") != nil {
+			return fmt.Sprintf("oapigen.%s%sTextResponse", name, code)
+		}
+	}
+	return fmt.Sprintf("oapigen.%s%sResponse", name, code)
+}
+
+// ToGoName converts the parameter name to the field name of oapigen (e.g. user_id -> UserId).
+func ToGoName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' || r == ' ' })
+	for i, x := range parts {
+		parts[i] = ToTitle(x)
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeDoc(t *testing.T) {
+	generated := []string{
+		"Suggest is endpoint of POST /emoji/suggest",
+		"new description",
+		"",
+		`* body   :requestBody                         -- "request.Body *oapigen.SuggestJSONRequestBody"`,
+	}
+
+	tests := []struct {
+		name     string
+		existing string
+		want     []string
+	}{
+		{name: "empty", existing: "", want: generated},
+		{name: "stale",
+			existing: strings.Join([]string{
+				"Suggest is endpoint of POST /emoji/suggest",
+				"old description",
+				"",
+				`* body  :requestBody                         -- "need: var body oapigen.SuggestJSONBody; gctx.ShouldBindJSON(&body); "`,
+			}, "\n"),
+			want: generated,
+		},
+		{name: "with-hand-written",
+			existing: strings.Join([]string{
+				"Suggest is endpoint of POST /emoji/suggest",
+				"old description",
+				"",
+				`* body  :requestBody                         -- "need: var body oapigen.SuggestJSONBody; gctx.ShouldBindJSON(&body); "`,
+				"",
+				"NOTE: hand-written memo",
+			}, "\n"),
+			want: append(append([]string{}, generated...), "", "NOTE: hand-written memo"),
+		},
+		{name: "not-generated",
+			existing: "Suggest is hand-written",
+			want:     append(append([]string{}, generated...), "", "Suggest is hand-written"),
+		},
+		{name: "deprecated",
+			existing: strings.Join([]string{
+				"Suggest is endpoint of POST /emoji/suggest",
+				"",
+				deprecatedMarker,
+			}, "\n"),
+			want: generated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeDoc(tt.existing, generated)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("MergeDoc() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ErrOutOfSync is returned by run() with --check, when some files are needed to be regenerated.
+var ErrOutOfSync = errors.New("out of sync")
+
+// emitter collects the generated files, and writes them all at once after the generation is finished.
+type emitter struct {
+	files []*emittedFile
+	stale int // the number of stale methods, not pruned
+}
+
+type emittedFile struct {
+	path string
+	code []byte // formatted
+	prev []byte // the content on disk (nil if the file is not existed)
+}
+
+// Emit adds the file to be written. The code is formatted with go/format.
+// If the file is already emitted, the previous one is replaced.
+func (e *emitter) Emit(path string, code []byte) error {
+	formatted, err := format.Source(code)
+	if err != nil {
+		return fmt.Errorf("format %s: %w", path, err)
+	}
+	for _, f := range e.files {
+		if f.path == path {
+			f.code = formatted
+			return nil
+		}
+	}
+	e.files = append(e.files, &emittedFile{path: path, code: formatted})
+	return nil
+}
+
+// Changed returns the files whose content differs from the file on disk.
+func (e *emitter) Changed() ([]*emittedFile, error) {
+	var changed []*emittedFile
+	for _, f := range e.files {
+		prev, err := os.ReadFile(f.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("read %s: %w", f.path, err)
+		}
+		f.prev = prev
+		if prev != nil && bytes.Equal(prev, f.code) {
+			continue
+		}
+		changed = append(changed, f)
+	}
+	return changed, nil
+}
+
+// Write writes the changed files.
+func (e *emitter) Write(options Options) error {
+	changed, err := e.Changed()
+	if err != nil {
+		return err
+	}
+	if options.Debug {
+		for _, f := range e.files {
+			if f.prev != nil && bytes.Equal(f.prev, f.code) {
+				log.Printf("emit skip :: filename=%s", f.path)
+			}
+		}
+	}
+
+	if options.DryRun {
+		for _, f := range changed {
+			if err := f.WriteDiff(os.Stdout); err != nil {
+				return fmt.Errorf("diff %s: %w", f.path, err)
+			}
+		}
+	}
+
+	if options.Check {
+		for _, f := range changed {
+			log.Printf("out of sync :: create=%5v filename=%s", f.prev == nil, f.path)
+		}
+		if len(changed) > 0 || e.stale > 0 {
+			return fmt.Errorf("%w: %d files are needed to be regenerated, %d stale methods are found", ErrOutOfSync, len(changed), e.stale)
+		}
+		return nil
+	}
+	if options.DryRun {
+		return nil
+	}
+
+	for _, f := range changed {
+		log.Printf("emit file :: create=%5v filename=%s", f.prev == nil, f.path)
+		tmpname := f.path + ".mv"
+		if err := os.WriteFile(tmpname, f.code, 0644); err != nil {
+			return fmt.Errorf("create %s: %w", f.path, err)
+		}
+		if err := os.Rename(tmpname, f.path); err != nil {
+			return fmt.Errorf("rename %s: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+// WriteDiff writes the unified diff between the file on disk and the generated code.
+func (f *emittedFile) WriteDiff(w io.Writer) error {
+	fromFile := "a/" + f.path
+	if f.prev == nil {
+		fromFile = "/dev/null"
+	}
+	return difflib.WriteUnifiedDiff(w, difflib.UnifiedDiff{
+		A:        splitLines(string(f.prev)),
+		B:        splitLines(string(f.code)),
+		FromFile: fromFile,
+		ToFile:   "b/" + f.path,
+		Context:  3,
+	})
+}
+
+// splitLines splits s into the lines, without the blank line at the end which difflib.SplitLines() adds.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	last := len(lines) - 1
+	if lines[last] == "" {
+		return lines[:last]
+	}
+	lines[last] += "\n"
+	return lines
+}
+
+// CommandLine returns the arguments used in the "Generated by" header.
+// The options that don't affect the generated code are dropped, to keep the output stable.
+func CommandLine(args []string) string {
+	r := make([]string, 0, len(args))
+	for _, arg := range args {
+		name, _, _ := strings.Cut(arg, "=")
+		switch name {
+		case "--check", "--debug", "--dry-run", "--prune":
+			continue
+		}
+		r = append(r, arg)
+	}
+	return strings.Join(r, " ")
+}
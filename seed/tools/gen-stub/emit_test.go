@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	pkgdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	options := setupFixture(t, "prune")
+	options.Prune = PruneModeDelete
+	options.DryRun = true
+
+	before, err := os.ReadFile("api/greeting.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	// the diff is written to stdout
+	out, err := os.Create("dry-run.diff")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = out
+	err = run(options)
+	os.Stdout = stdout
+	out.Close() // nolint
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	assertGolden(t, pkgdir, "dry-run.diff", "testdata/prune/dry-run.diff.golden")
+
+	// no files are written
+	after, err := os.ReadFile("api/greeting.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("api/greeting.go should not be modified with --dry-run")
+	}
+	if _, err := os.Stat("api/controller.go"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("api/controller.go should not be created with --dry-run (err=%v)", err)
+	}
+}
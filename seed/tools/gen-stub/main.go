@@ -33,8 +33,14 @@ type Options struct {
 	DstDirList []string
 	DocName    string
 	Prefix     string
+	Prune      string // "", "deprecate" or "delete"
+	Check      bool
+	DryRun     bool
+	Test       bool
+	Target     *Target
 
 	Debug           bool
+	RootDir         string // module root (from go list -m), relative paths are resolved from here
 	GuessImportPath func(dir string) string
 }
 
@@ -48,17 +54,24 @@ func main() {
 	pflag.StringSliceVar(&options.DstDirList, "dst", nil, "destination directory")
 	pflag.StringVar(&options.DocName, "doc", "", "target openapi doc (OAS3.0)")
 	pflag.StringVar(&options.Prefix, "prefix", "", "prefix")
+	pflag.StringVar(&options.Prune, "prune", "", "handle the stale controller methods whose operation is removed (deprecate|delete)")
+	pflag.Lookup("prune").NoOptDefVal = PruneModeDeprecate
+	pflag.BoolVar(&options.Check, "check", false, "exit with non-zero status if dst is out of sync (no files are written)")
+	pflag.BoolVar(&options.DryRun, "dry-run", false, "print the unified diff of the files to be changed, instead of writing them")
+	pflag.BoolVar(&options.Test, "test", false, "emit <tag>_test.go skeletons (only the tests not defined yet)")
 	pflag.BoolVar(&options.Debug, "debug", false, "debug")
+	targetName := pflag.String("target", "strict", "kind of the server interface in --src (strict|chi|echo|gin)")
 	pflag.Parse()
 
 	if err := func() error {
-		var modinfo struct {
+		type moduleInfo struct {
 			Path      string
 			Main      bool
 			Dir       string
 			GoMod     string
 			GoVersion string
 		}
+		var modinfo moduleInfo
 
 		args := []string{"go", "list", "-m", "-json"}
 		if options.BasePkg != "" {
@@ -70,41 +83,90 @@ func main() {
 		} else if err != nil {
 			return err
 		}
-		if err := json.Unmarshal(out, &modinfo); err != nil {
-			return fmt.Errorf("unexpected input `go list -m json`: %w", err)
+		// with go.work, multiple modules are listed. the main module including the current directory is used
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(out))
+		for dec.More() {
+			var m moduleInfo
+			if err := dec.Decode(&m); err != nil {
+				return fmt.Errorf("unexpected input `go list -m json`: %w", err)
+			}
+			if modinfo.Path == "" || (m.Main && InDir(cwd, m.Dir)) {
+				modinfo = m
+			}
 		}
 
 		options.BasePkg = modinfo.Path
 		if options.Debug {
-			log.Printf("[DEBUG] base package is %q (from go list -m)", options.BasePkg)
+			log.Printf("[DEBUG] base package is %q, root dir is %q (from go list -m)", options.BasePkg, modinfo.Dir)
 		}
-		cwd, err := os.Getwd()
-		if err != nil {
-			return err
+		if modinfo.Main {
+			options.RootDir = modinfo.Dir
+		} else {
+			options.RootDir = cwd
 		}
 
 		options.GuessImportPath = func(name string) string {
-			suffix, _ := filepath.Rel(modinfo.Dir, filepath.Join(cwd, name))
-			return path.Join(options.BasePkg, suffix)
+			abspath, _ := filepath.Abs(name)
+			suffix, _ := filepath.Rel(modinfo.Dir, abspath)
+			return path.Join(options.BasePkg, filepath.ToSlash(suffix))
 		}
 		return nil
 	}(); err != nil {
-		log.Printf("load modinfo is failed. %+v", err)
+		log.Fatalf("!! load modinfo is failed. %+v", err)
 	}
 
 	if options.SrcDir == "" || options.DstDirList == nil || options.DocName == "" {
 		flag.Usage()
 		os.Exit(1)
 	}
+	switch options.Prune {
+	case "", PruneModeDeprecate, PruneModeDelete:
+	default:
+		log.Fatalf("!! unexpected --prune value: %q (deprecate|delete)", options.Prune)
+	}
+	target, err := LookupTarget(*targetName)
+	if err != nil {
+		log.Fatalf("!! %+v", err)
+	}
+	options.Target = target
 	if err := run(options); err != nil {
 		log.Fatalf("!! %+v", err)
 	}
 }
 
+// InDir reports whether the path is the dir or is under the dir (e.g. /a/b/c is in /a/b, but /a/bc is not).
+func InDir(path string, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// Path resolves the relative path from the module root (the result is relative to the current directory).
+func (o Options) Path(name string) string {
+	if filepath.IsAbs(name) || o.RootDir == "" {
+		return name
+	}
+	abspath := filepath.Join(o.RootDir, name)
+	cwd, err := os.Getwd()
+	if err != nil {
+		return abspath
+	}
+	if rel, err := filepath.Rel(cwd, abspath); err == nil {
+		return rel
+	}
+	return abspath
+}
+
 func run(options Options) error {
-	srcdir := options.SrcDir
-	dstdirList := options.DstDirList
-	docname := options.DocName
+	srcdir := options.Path(options.SrcDir)
+	dstdirList := make([]string, len(options.DstDirList))
+	for i, dstdir := range options.DstDirList {
+		dstdirList[i] = options.Path(dstdir)
+	}
+	docname := options.Path(options.DocName)
 	prefix := options.Prefix
 
 	fset := token.NewFileSet()
@@ -122,7 +184,8 @@ func run(options Options) error {
 	}
 
 	// scan dst dir
-	dstFiles := map[string]*file{} // normalized tag -> file
+	dstFiles := map[string]*file{}            // normalized tag -> file
+	constructors := map[string]*constructor{} // <dstdir>:<controller type name> -> constructor
 	{
 		for _, dstdir := range dstdirList {
 			if err := os.MkdirAll(dstdir, 0744); err != nil {
@@ -137,37 +200,40 @@ func run(options Options) error {
 					tag := TagFromFileName(filename)
 
 					methods := map[string]*ast.FuncDecl{}
+					var recv ast.Expr // e.g. *EmojiController, *EmojiController[T]
 					for _, decl := range f.Decls {
 						decl, ok := decl.(*ast.FuncDecl)
 						if !ok {
 							continue
 						}
+						if decl.Recv == nil && strings.HasPrefix(decl.Name.Name, "New") && strings.HasSuffix(decl.Name.Name, "Controller") {
+							ctor, err := ScanConstructor(fset, f, decl)
+							if err != nil {
+								return err
+							}
+							constructors[dstdir+":"+strings.TrimPrefix(decl.Name.Name, "New")] = ctor
+							continue
+						}
 						if decl.Recv == nil || len(decl.Recv.List) == 0 {
 							continue
 						}
 
-						typeName := ""
-						switch typ := decl.Recv.List[0].Type.(type) {
-						case *ast.StarExpr:
-							typeName = typ.X.(*ast.Ident).Name
-						case *ast.Ident:
-							typeName = typ.Name
-						default:
-							fmt.Fprintln(os.Stderr, "----------------------------------------") // nolint
-							printer.Fprint(os.Stderr, fset, decl)                               // nolint
-							fmt.Fprintln(os.Stderr, "----------------------------------------") // nolint
-							panic(fmt.Sprintf("unexpected type: %T", typ))
+						typeName, err := ReceiverTypeName(decl.Recv.List[0].Type)
+						if err != nil {
+							return fmt.Errorf("%s: %s.%s: %w", fset.Position(decl.Recv.Pos()), filepath.Base(filename), decl.Name.Name, err)
 						}
 						if !strings.HasSuffix(typeName, "Controller") {
 							continue
-
+						}
+						if recv == nil {
+							recv = decl.Recv.List[0].Type
 						}
 						if !ast.IsExported(decl.Name.Name) {
 							continue
 						}
 						methods[decl.Name.Name] = decl
 					}
-					dstFiles[tag] = &file{path: filename, syntax: f, methods: methods}
+					dstFiles[tag] = &file{path: filename, syntax: f, methods: methods, recv: recv}
 				}
 			}
 		}
@@ -180,9 +246,11 @@ func run(options Options) error {
 	}
 
 	// https://github.com/josharian/impl like generate skeleton
+	srcImports := map[string]string{"oapigen": options.GuessImportPath(srcdir)} // package name -> import path (used in method signatures)
+	found := false
 	for _, pkg := range pkgs {
 		for _, f := range pkg.Files {
-			ob := f.Scope.Lookup("StrictServerInterface")
+			ob := f.Scope.Lookup(options.Target.InterfaceName)
 			if ob == nil {
 				continue
 			}
@@ -194,8 +262,35 @@ func run(options Options) error {
 			if !ok {
 				continue
 			}
+			found = true
+			for _, im := range f.Imports {
+				path, _ := strconv.Unquote(im.Path.Value)
+				name := PackageName(path)
+				if im.Name != nil {
+					name = im.Name.Name
+				}
+				srcImports[name] = path
+			}
 			for _, p := range typ.Methods.List {
-				state.Add(p)
+				if err := state.Add(p); err != nil {
+					return fmt.Errorf("%s: %w", fset.Position(p.Pos()), err)
+				}
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not found in %s (--target=%s)", options.Target.InterfaceName, srcdir, options.Target)
+	}
+	addImports := func(f *file, fn *ast.FuncType) {
+		for _, name := range PackageNamesInUse(fn) {
+			path, ok := srcImports[name]
+			if !ok {
+				continue
+			}
+			if name == PackageName(path) && name != "oapigen" {
+				astutil.AddImport(fset, f.syntax, path)
+			} else {
+				astutil.AddNamedImport(fset, f.syntax, name, path)
 			}
 		}
 	}
@@ -211,12 +306,22 @@ func run(options Options) error {
 		typeNames  []string
 	}
 	dstdirToTypeSetMap := map[string]*typeSet{}
+	emitter := &emitter{}
+	visited := map[*file]bool{}
+	testgen := &testGenerator{fset: fset, emitter: emitter, packages: map[string]*testPackage{}, srcImportPath: options.GuessImportPath(srcdir), importPath: options.GuessImportPath, target: options.Target}
+	prune := func(f *file, defs []*def) bool {
+		stale := StaleMethods(f, defs)
+		if options.Prune == "" {
+			emitter.stale += len(stale)
+		}
+		return PruneMethods(f, stale, options.Prune)
+	}
 
 	if err := state.EachByTag(func(tag string, defs []*def) error {
 		xGoPackage := defs[0].xGoPackage
 		// log.Printf("\t🔢 x-go-package:%q\ttag:%q", xGoPackage, tag)
 		dstdir := SelectDirByXGoPackage(xGoPackage, dstdirList)
-		pkgname := filepath.Base(dstdir)
+		pkgname := PackageNameFromDir(dstdir)
 
 		normalziedTag := NormalizeTag(tag)
 		f, updated := dstFiles[normalziedTag]
@@ -229,21 +334,7 @@ func run(options Options) error {
 			}
 			f = &file{path: filename, syntax: syntax, methods: map[string]*ast.FuncDecl{}}
 		}
-
-		{
-			// TODO: 特定の位置以外から実行したときに壊れる
-			found := false
-			for _, im := range f.syntax.Imports {
-				if im.Name != nil && im.Name.Name == "oapigen" {
-					found = true
-					break
-				}
-			}
-			if !found {
-				astutil.AddImport(fset, f.syntax, "context")
-				astutil.AddNamedImport(fset, f.syntax, "oapigen", options.GuessImportPath(srcdir))
-			}
-		}
+		visited[f] = true
 
 		parts := strings.Split(tag, "-")
 		camelized := make([]string, len(parts))
@@ -279,7 +370,7 @@ func run(options Options) error {
 
 			// extract method parameters signature
 			fn := def.field.Type.(*ast.FuncType)
-			if len(fn.Params.List[0].Names) > 0 {
+			if len(fn.Params.List) > 0 && len(fn.Params.List[0].Names) > 0 {
 				for _, p := range fn.Params.List[1:] {
 					if len(p.Names) > 0 {
 						name := p.Names[0].Name
@@ -309,6 +400,11 @@ func run(options Options) error {
 					if len(results.List[1].Names) == 0 {
 						results.List[1].Names = []*ast.Ident{{Name: "err", NamePos: results.Pos() + 2}}
 					}
+				} else if len(results.List) == 1 { // e.g. echo's Suggest(ctx echo.Context) error
+					r0 := results.List[0]
+					if typename, ok := r0.Type.(*ast.Ident); ok && typename.Name == "error" && len(r0.Names) == 0 {
+						r0.Names = []*ast.Ident{{Name: "err", NamePos: results.Pos() + 1}}
+					}
 				}
 			}
 
@@ -318,61 +414,14 @@ func run(options Options) error {
 			}
 
 			// doc string
-			var comments []string
-			{
-				comments = append(comments, fmt.Sprintf("%s is endpoint of %s %s", name, def.method, def.path))
-				doc := def.op.Summary
-				if doc == "" {
-					doc = def.op.Description
-				}
-				for _, line := range strings.Split(doc, "\n") {
-					if line != "" {
-						comments = append(comments, line)
-					}
-				}
-				// parametersの情報を追加
-				// e.g.) * body :requestBody -- "need: var body oapigen.CreateKnowledgeJSONBody; gctx.ShouldBindJSON(&body); "
-				if len(def.op.Parameters) > 0 || def.op.RequestBody != nil {
-					comments = append(comments, "")
-					for _, p := range def.op.Parameters {
-						pname := p.Value.Name
-						if p.Value != nil && p.Value.Schema != nil && p.Value.Schema.Value != nil && p.Value.Schema.Value.Default != nil {
-							defaultValue := p.Value.Schema.Value.Default
-							if v, ok := defaultValue.(string); ok {
-								pname = fmt.Sprintf("%s default=%q", pname, v)
-							} else {
-								pname = fmt.Sprintf("%s default=%v", pname, defaultValue)
-							}
-						} else if !p.Value.Required {
-							pname = fmt.Sprintf("%s default=nil", pname)
-						}
-						comments = append(comments, fmt.Sprintf("* %-6s:%-35s -- %q", p.Value.In, pname, p.Value.Description))
-					}
-					if def.op.RequestBody != nil {
-						comments = append(comments, fmt.Sprintf("* %-6s:%-35s -- %q", "body", "requestBody", fmt.Sprintf("need: var body oapigen.%sJSONBody; gctx.ShouldBindJSON(&body); ", name)))
-					}
-				}
-			}
+			comments := DocComments(name, def, options.Target)
 
 			// already defined
 			if decl, ok := f.methods[name]; ok {
-				// modify comments, if need
-				if strings.Join(comments, "\n") != strings.TrimSpace(decl.Doc.Text()) {
+				// modify comments, if need (hand-written paragraphs are kept)
+				if comments := MergeDoc(decl.Doc.Text(), comments); strings.Join(comments, "\n") != strings.TrimSpace(decl.Doc.Text()) {
 					modified = true
-
-					prevComment := decl.Doc
-					clines := make([]*ast.Comment, len(comments))
-					pos := decl.Pos() - 1 // hack
-					for i, line := range comments {
-						clines[i] = &ast.Comment{Slash: pos, Text: "// " + line}
-					}
-					decl.Doc = &ast.CommentGroup{List: clines}
-					for i, cg := range f.syntax.Comments {
-						if cg == prevComment {
-							f.syntax.Comments[i] = decl.Doc
-							break
-						}
-					}
+					replaceDoc(f, decl, comments)
 				}
 
 				// update method signature, if need
@@ -439,19 +488,39 @@ func run(options Options) error {
 							return true
 						})
 						decl.Type = def.field.Type.(*ast.FuncType)
+						addImports(f, decl.Type)
 					}
 				}
 				continue
 			}
 
 			// not defined yet
-
+			addImports(f, fn)
 			fmt.Fprintln(w, "\n// "+strings.TrimSpace(strings.Join(comments, "\n// "))) // nolint
-			fmt.Fprintf(w, "func (c *%s) %s", typeName, name)                           // nolint
-			fmt.Fprint(w, buf.String()[4:])                                             // nolint
-			fmt.Fprintln(w, " {")                                                       // nolint
-			fmt.Fprintln(w, "\treturn")                                                 // nolint
-			fmt.Fprintln(w, "}")                                                        // nolint
+			recv := "*" + typeName
+			if f.recv != nil {
+				tmpBuf.Reset()
+				if err := printer.Fprint(tmpBuf, fset, f.recv); err != nil {
+					return err
+				}
+				recv = tmpBuf.String() // e.g. *EmojiController[T]
+			}
+			fmt.Fprintf(w, "func (%s %s) %s", ReceiverName(fn), recv, name) // nolint
+			fmt.Fprint(w, buf.String()[4:])                                 // nolint
+			fmt.Fprintln(w, " {")                                           // nolint
+			fmt.Fprintln(w, "\treturn")                                     // nolint
+			fmt.Fprintln(w, "}")                                            // nolint
+		}
+
+		// methods whose operation is removed
+		if prune(f, defs) {
+			modified = true
+		}
+
+		if options.Test {
+			if err := testgen.EmitTagTests(dstdir, normalziedTag, typeName, AggregateTypeName(prefix, xGoPackage, pkgname), constructors[dstdir+":"+typeName], defs); err != nil {
+				return fmt.Errorf("emit tests, tag=%s: %w", tag, err)
+			}
 		}
 
 		if fileBuf.Len() == 0 && !modified {
@@ -461,44 +530,53 @@ func run(options Options) error {
 			return nil
 		}
 
-		if created := !updated; options.Debug || created {
-			log.Printf("emit file :: create=%5v tag=%-25s filename=%s", created, tag, f.path)
-		}
-		wf, err := os.Create(f.path + ".mv")
-		if err != nil {
-			return fmt.Errorf("create %s: %w", f.path, err)
-		}
-		if err := printer.Fprint(wf, fset, f.syntax); err != nil {
+		code := new(bytes.Buffer)
+		if err := printer.Fprint(code, fset, f.syntax); err != nil {
 			return fmt.Errorf("write %s: %w", f.path, err)
 		}
-		if _, err := io.Copy(wf, fileBuf); err != nil {
+		if _, err := io.Copy(code, fileBuf); err != nil {
 			return fmt.Errorf("write %s: %w", f.path, err)
 		}
-		if err := os.Rename(wf.Name(), f.path); err != nil {
-			return fmt.Errorf("rename %s: %w", f.path, err)
-		}
-		return nil
+		return emitter.Emit(f.path, code.Bytes())
 	}); err != nil {
 		return err
 	}
 
+	// newHandler() for the generated tests
+	for _, dstdir := range sortedKeys(testgen.packages) {
+		if err := testgen.EmitHandlerHelper(dstdir); err != nil {
+			return fmt.Errorf("emit tests: %w", err)
+		}
+	}
+
+	// files whose tag is removed from the openapi doc
+	for _, tag := range sortedKeys(dstFiles) {
+		f := dstFiles[tag]
+		if visited[f] {
+			continue
+		}
+		if !prune(f, nil) {
+			continue
+		}
+		code := new(bytes.Buffer)
+		if err := printer.Fprint(code, fset, f.syntax); err != nil {
+			return fmt.Errorf("write %s: %w", f.path, err)
+		}
+		if err := emitter.Emit(f.path, code.Bytes()); err != nil {
+			return err
+		}
+	}
+
 	// controllerをmountするfile
 	for dstdir, typeset := range dstdirToTypeSetMap {
-		pkgname := filepath.Base(dstdir)
+		pkgname := PackageNameFromDir(dstdir)
 		controllerTypeNames := typeset.typeNames
 		if len(controllerTypeNames) == 0 {
 			continue
 		}
 
 		filename := filepath.Join(dstdir, "controller.go")
-		log.Printf("emit file :: filename=%s", filename)
-
-		wf, err := os.Create(filename)
-		if err != nil {
-			return fmt.Errorf("create %s", filename)
-		}
-		defer wf.Close() // nolint
-
+		wf := new(bytes.Buffer)
 		w = wf
 		if typeset.xGoPackage != "" || len(dstdirList) == 1 { // --dstの先頭のディレクトリは全てのcontrollerを埋め込んだものとして扱う
 			sort.Strings(controllerTypeNames)
@@ -507,13 +585,24 @@ func run(options Options) error {
 			if typeset.xGoPackage != "" {
 				prefix = ToTitle(typeset.xGoPackage)
 			}
-			pkgname := filepath.Base(dstdir)
+			pkgname := PackageNameFromDir(dstdir)
 			prefix = prefix + ToTitle(pkgname)
 			typeName := fmt.Sprintf("%sController", prefix)
-			fmt.Fprintf(w, "// Generated by swagger/tools/gen-stub %s\n", strings.Join(os.Args[1:], " ")) // nolint
-			fmt.Fprintf(w, "package %s", pkgname)                                                         // nolint
-			fmt.Fprintln(w, "")                                                                           // nolint
-			fmt.Fprintln(w, "")                                                                           // nolint
+			deps := NewDependencies()
+			for _, name := range controllerTypeNames {
+				deps.Add(name, constructors[dstdir+":"+name])
+			}
+			fmt.Fprintf(w, "// Generated by swagger/tools/gen-stub %s\n", CommandLine(os.Args[1:])) // nolint
+			fmt.Fprintf(w, "package %s", pkgname)                                                   // nolint
+			fmt.Fprintln(w, "")                                                                     // nolint
+			fmt.Fprintln(w, "")                                                                     // nolint
+			// CODE -- import:
+			if len(deps.imports) > 0 {
+				fmt.Fprintln(w, "import (") // nolint
+				deps.WriteImports(w)
+				fmt.Fprintln(w, ")") // nolint
+				fmt.Fprintln(w, "")  // nolint
+			}
 			// CODE -- define struct:
 			fmt.Fprintf(w, "// %s :\n", typeName)          // nolint
 			fmt.Fprintf(w, "type %s struct {\n", typeName) // nolint
@@ -522,30 +611,38 @@ func run(options Options) error {
 			}
 			fmt.Fprintf(w, "}\n") // nolint
 			fmt.Fprintln(w, "")   // nolint
+			// CODE -- define dependencies:
+			deps.WriteStruct(w)
 			// CODE --  define factory function:
-			fmt.Fprintf(w, "// New%s :\n", typeName)                  // nolint
-			fmt.Fprintf(w, "func New%s() *%s{\n", typeName, typeName) // nolint
-			fmt.Fprintf(w, "\treturn &%s{\n", typeName)               // nolint
+			fmt.Fprintf(w, "// New%s :\n", typeName)                                   // nolint
+			fmt.Fprintf(w, "func New%s(deps Dependencies) *%s{\n", typeName, typeName) // nolint
+			fmt.Fprintf(w, "\treturn &%s{\n", typeName)                                // nolint
 			for _, name := range controllerTypeNames {
-				fmt.Fprintf(w, "\t\t%s: New%s(),\n", name, name) // nolint
+				fmt.Fprintf(w, "\t\t%s: New%s(%s),\n", name, name, deps.Args(name)) // nolint
 			}
 			fmt.Fprintf(w, "\t}\n") // nolint
 			fmt.Fprintf(w, "}\n")   // nolint
 		} else { // root dstのpackageの場合には諸々をimportしてembeddedする (importが必要になる)
 			sort.Strings(controllerTypeNames)
 			typeName := fmt.Sprintf("%s%sController", ToTitle(prefix), ToTitle(pkgname))
-			fmt.Fprintf(w, "// Generated by seed/tools/gen-stub %s\n", strings.Join(os.Args[1:], " ")) // nolint
-			fmt.Fprintf(w, "package %s", pkgname)                                                      // nolint
-			fmt.Fprintln(w, "")                                                                        // nolint
-			// CODE --  import:
-			fmt.Fprintln(w, "import (") // nolint
+			deps := NewDependencies()
+			for _, name := range controllerTypeNames {
+				deps.Add(name, constructors[dstdir+":"+name])
+			}
 			for _, dstdir := range dstdirList {
 				typeset, ok := dstdirToTypeSetMap[dstdir]
 				if !ok || typeset.xGoPackage == "" || len(typeset.typeNames) == 0 {
 					continue
 				}
-				fmt.Fprintf(w, "\t%s %q\n", typeset.xGoPackage, options.GuessImportPath(dstdir)) // nolint
+				name := AggregateTypeName(prefix, typeset.xGoPackage, PackageNameFromDir(dstdir))
+				deps.AddPackage(name, typeset.xGoPackage, options.GuessImportPath(dstdir))
 			}
+			fmt.Fprintf(w, "// Generated by seed/tools/gen-stub %s\n", CommandLine(os.Args[1:])) // nolint
+			fmt.Fprintf(w, "package %s", pkgname)                                                // nolint
+			fmt.Fprintln(w, "")                                                                  // nolint
+			// CODE --  import:
+			fmt.Fprintln(w, "import (") // nolint
+			deps.WriteImports(w)
 			fmt.Fprintln(w, ")") // nolint
 			fmt.Fprintln(w, "")  // nolint
 			// CODE --  define struct:
@@ -564,19 +661,21 @@ func run(options Options) error {
 				if typeset.xGoPackage != "" {
 					prefix = ToTitle(typeset.xGoPackage)
 				}
-				pkgname := filepath.Base(dstdir)
+				pkgname := PackageNameFromDir(dstdir)
 				prefix = prefix + ToTitle(pkgname)
 				name := fmt.Sprintf("%sController", prefix)
 				fmt.Fprintf(w, "\t*%s.%s\n", typeset.xGoPackage, name) // nolint
 			}
 			fmt.Fprintf(w, "}\n") // nolint
 			fmt.Fprintln(w, "")   // nolint
+			// CODE -- define dependencies:
+			deps.WriteStruct(w)
 			// CODE --  define factory function:
-			fmt.Fprintf(w, "// New%s :\n", typeName)                   // nolint
-			fmt.Fprintf(w, "func New%s() *%s {\n", typeName, typeName) // nolint
-			fmt.Fprintf(w, "\treturn &%s{\n", typeName)                // nolint
+			fmt.Fprintf(w, "// New%s :\n", typeName)                                    // nolint
+			fmt.Fprintf(w, "func New%s(deps Dependencies) *%s {\n", typeName, typeName) // nolint
+			fmt.Fprintf(w, "\treturn &%s{\n", typeName)                                 // nolint
 			for _, name := range controllerTypeNames {
-				fmt.Fprintf(w, "\t\t%s: New%s(),\n", name, name) // nolint
+				fmt.Fprintf(w, "\t\t%s: New%s(%s),\n", name, name, deps.Args(name)) // nolint
 			}
 			for _, dstdir := range dstdirList {
 				typeset, ok := dstdirToTypeSetMap[dstdir]
@@ -587,22 +686,26 @@ func run(options Options) error {
 				if typeset.xGoPackage != "" {
 					prefix = ToTitle(typeset.xGoPackage)
 				}
-				pkgname := filepath.Base(dstdir)
+				pkgname := PackageNameFromDir(dstdir)
 				prefix = prefix + ToTitle(pkgname)
 				name := fmt.Sprintf("%sController", prefix)
-				fmt.Fprintf(w, "\t\t%s: %s.New%s(),\n", name, typeset.xGoPackage, name) // nolint
+				fmt.Fprintf(w, "\t\t%s: %s.New%s(%s),\n", name, typeset.xGoPackage, name, deps.Args(name)) // nolint
 			}
 			fmt.Fprintf(w, "\t}\n") // nolint
 			fmt.Fprintf(w, "}\n")   // nolint
 		}
+		if err := emitter.Emit(filename, wf.Bytes()); err != nil {
+			return err
+		}
 	}
-	return nil
+	return emitter.Write(options)
 }
 
 type file struct {
 	path    string
 	syntax  *ast.File
 	methods map[string]*ast.FuncDecl
+	recv    ast.Expr // the receiver type of the existing methods (nil if not found)
 }
 
 type def struct {
@@ -635,11 +738,17 @@ func newState(doc *openapi3.T) *state {
 			id := ToTitle(op.OperationID) // foo -> Foo
 			xGoPackage := ""
 			if len(op.Extensions) > 0 {
-				if v, ok := op.Extensions["x-go-package"]; ok {
-					xGoPackage = v.(string)
-					if v, err := strconv.Unquote(xGoPackage); err == nil {
-						xGoPackage = v
-					}
+				switch v := op.Extensions["x-go-package"].(type) {
+				case string:
+					xGoPackage = v
+				case json.RawMessage:
+					xGoPackage = string(v)
+				case nil:
+				default:
+					log.Printf("unexpected x-go-package value: %#+v (operationId=%s)", v, op.OperationID)
+				}
+				if v, err := strconv.Unquote(xGoPackage); err == nil {
+					xGoPackage = v
 				}
 			}
 			// log.Printf("ℹ️ x-go-package:%q\ttag:%v\toperationid:%q", xGoPackage, op.Tags, op.OperationID)
@@ -649,10 +758,16 @@ func newState(doc *openapi3.T) *state {
 	return &state{defs: map[string][]*def{}, endpoints: endpoints}
 }
 
-func (s *state) Add(method *ast.Field) {
+func (s *state) Add(method *ast.Field) error {
+	if len(method.Names) == 0 {
+		return fmt.Errorf("embedded interface is not supported")
+	}
+	if _, ok := method.Type.(*ast.FuncType); !ok {
+		return fmt.Errorf("unexpected method type: %T", method.Type)
+	}
 	op, ok := s.endpoints[method.Names[0].Name]
 	if !ok {
-		panic(fmt.Sprintf("%q is not found, maybe --doc option is invalid?", method.Names[0].Name))
+		return fmt.Errorf("%q is not found, maybe --doc option is invalid?", method.Names[0].Name)
 	}
 	tag := "notags" // tags[0]が存在しない場合
 	if len(op.Tags) > 0 {
@@ -663,6 +778,7 @@ func (s *state) Add(method *ast.Field) {
 		s.tags = append(s.tags, tag)
 	}
 	s.defs[tag] = append(ops, &def{op: op.Operation, field: method, method: op.method, path: op.path, xGoPackage: op.xGoPackage})
+	return nil
 }
 
 func (s *state) EachByTag(fn func(string, []*def) error) error {
@@ -675,6 +791,32 @@ func (s *state) EachByTag(fn func(string, []*def) error) error {
 	return nil
 }
 
+// PackageNameFromDir returns the package name of the directory (e.g. ./api -> api, . -> <name of the current directory>).
+func PackageNameFromDir(dir string) string {
+	if abspath, err := filepath.Abs(dir); err == nil {
+		dir = abspath
+	}
+	return filepath.Base(dir)
+}
+
+// ReceiverTypeName returns the type name of the method receiver (e.g. *Foo -> Foo, *Foo[T] -> Foo, Foo[K, V] -> Foo).
+func ReceiverTypeName(expr ast.Expr) (string, error) {
+	switch typ := expr.(type) {
+	case *ast.Ident:
+		return typ.Name, nil
+	case *ast.StarExpr:
+		return ReceiverTypeName(typ.X)
+	case *ast.ParenExpr:
+		return ReceiverTypeName(typ.X)
+	case *ast.IndexExpr: // generic type (a type parameter)
+		return ReceiverTypeName(typ.X)
+	case *ast.IndexListExpr: // generic type (type parameters)
+		return ReceiverTypeName(typ.X)
+	default:
+		return "", fmt.Errorf("unexpected receiver type: %T", typ)
+	}
+}
+
 // SelectDirByXGoPackage
 func SelectDirByXGoPackage(xGoPackage string, dirs []string) string {
 	// x-go-packageの指定がない場合には`--dst`で渡された先頭の値を利用する
@@ -698,6 +840,23 @@ func SelectDirByXGoPackage(xGoPackage string, dirs []string) string {
 	return dirs[0]
 }
 
+// AggregateTypeName returns the name of controller embedding all controllers in the package (e.g. ApiController).
+func AggregateTypeName(prefix string, xGoPackage string, pkgname string) string {
+	if xGoPackage != "" {
+		prefix = xGoPackage
+	}
+	return fmt.Sprintf("%s%sController", ToTitle(prefix), ToTitle(pkgname))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func TagFromFileName(filename string) string {
 	base := strings.TrimSuffix(filepath.Base(filename), ".go")
 	tag := strings.ReplaceAll(base, "_", "-") // foo_bar -> foo-bar
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"go/parser"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReceiverTypeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		recv    string
+		want    string
+		wantErr bool
+	}{
+		{name: "ident", recv: "EmojiController", want: "EmojiController"},
+		{name: "pointer", recv: "*EmojiController", want: "EmojiController"},
+		{name: "generic", recv: "*EmojiController[T]", want: "EmojiController"},
+		{name: "generic-multi", recv: "EmojiController[K, V]", want: "EmojiController"},
+		{name: "paren", recv: "(*EmojiController)", want: "EmojiController"},
+		{name: "unexpected", recv: "[]EmojiController", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tt.recv)
+			if err != nil {
+				t.Fatalf("unexpected error (parse): %+v", err)
+			}
+			got, err := ReceiverTypeName(expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReceiverTypeName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReceiverTypeName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInDir(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		dir  string
+		want bool
+	}{
+		{name: "same", path: "/src/emoji-api", dir: "/src/emoji-api", want: true},
+		{name: "sub", path: "/src/emoji-api/seed/tools", dir: "/src/emoji-api", want: true},
+		{name: "trailing-slash", path: "/src/emoji-api/seed", dir: "/src/emoji-api/", want: true},
+		{name: "sibling-with-same-prefix", path: "/src/emoji-api-v2", dir: "/src/emoji-api", want: false},
+		{name: "parent", path: "/src", dir: "/src/emoji-api", want: false},
+		{name: "root", path: "/src", dir: "/", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, dir := filepath.FromSlash(tt.path), filepath.FromSlash(tt.dir)
+			if got := InDir(path, dir); got != tt.want {
+				t.Errorf("InDir(%q, %q) = %v, want %v", path, dir, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRunFromSubdirectory runs the generation in the subdirectory of the module, the paths are resolved from RootDir.
+func TestRunFromSubdirectory(t *testing.T) {
+	pkgdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	options := setupFixture(t, "prune")
+	options.Prune = PruneModeDelete
+	options.RootDir, err = os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	options.GuessImportPath = func(dir string) string {
+		abspath, _ := filepath.Abs(dir)
+		suffix, _ := filepath.Rel(options.RootDir, abspath)
+		return "example.com/fixture/" + filepath.ToSlash(suffix)
+	}
+	if err := os.MkdirAll(filepath.Join("seed", "tools"), 0755); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if err := os.Chdir(filepath.Join("seed", "tools")); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if err := run(options); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	assertGolden(t, pkgdir, filepath.Join(options.RootDir, "api", "greeting.go"), "testdata/prune/greeting-delete.go.golden")
+	if _, err := os.Stat(filepath.Join(options.RootDir, "api", "controller.go")); err != nil {
+		t.Errorf("api/controller.go should be generated in the module root: %+v", err)
+	}
+	if _, err := os.Stat("api"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("nothing should be generated in the current directory, but api/ is found (err=%v)", err)
+	}
+}
+
+// TestRunWithGenericReceiver generates the missing method with the receiver of the existing methods.
+func TestRunWithGenericReceiver(t *testing.T) {
+	pkgdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	tests := []struct {
+		name   string
+		source string // api/greeting.go, Hello is defined but Greet is not
+		golden string
+	}{
+		{name: "pointer", golden: "testdata/generic/pointer-greeting.go.golden", source: `package api
+
+import (
+	"context"
+
+	oapigen "example.com/fixture/oapigen"
+)
+
+type GreetingController[T any] struct{}
+
+func (c *GreetingController[T]) Hello(ctx context.Context, request oapigen.HelloRequestObject) (response oapigen.HelloResponseObject, err error) {
+	return
+}
+`},
+		{name: "multi", golden: "testdata/generic/multi-greeting.go.golden", source: `package api
+
+import (
+	"context"
+
+	oapigen "example.com/fixture/oapigen"
+)
+
+type GreetingController[K comparable, V any] struct{}
+
+func (c GreetingController[K, V]) Hello(ctx context.Context, request oapigen.HelloRequestObject) (response oapigen.HelloResponseObject, err error) {
+	return
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := setupFixture(t, "testgen")
+			if err := os.MkdirAll("api", 0755); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			if err := os.WriteFile(filepath.Join("api", "greeting.go"), []byte(tt.source), 0644); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+
+			if err := run(options); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			assertGolden(t, pkgdir, "api/greeting.go", tt.golden)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"log"
+	"strings"
+)
+
+const (
+	PruneModeDeprecate = "deprecate" // add "Deprecated: operation removed" to the doc comment
+	PruneModeDelete    = "delete"    // delete the method
+)
+
+const deprecatedMarker = "Deprecated: operation removed"
+
+// isGeneratedMethod reports whether the method is generated by gen-stub (the doc comment starts with "<name> is endpoint of").
+func isGeneratedMethod(decl *ast.FuncDecl) bool {
+	return decl.Doc != nil && strings.HasPrefix(decl.Doc.Text(), decl.Name.Name+" is endpoint of ")
+}
+
+// StaleMethods returns the generated methods whose operation is no longer found in the openapi doc.
+func StaleMethods(f *file, defs []*def) []*ast.FuncDecl {
+	names := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		names[def.field.Names[0].Name] = true
+	}
+
+	var r []*ast.FuncDecl
+	for _, decl := range f.syntax.Decls { // keep the order of declarations
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok || f.methods[decl.Name.Name] != decl {
+			continue
+		}
+		if names[decl.Name.Name] || !isGeneratedMethod(decl) {
+			continue
+		}
+		r = append(r, decl)
+	}
+	return r
+}
+
+// PruneMethods handles the stale methods by mode. If mode is "", only warnings are reported.
+func PruneMethods(f *file, stale []*ast.FuncDecl, mode string) (modified bool) {
+	for _, decl := range stale {
+		name := decl.Name.Name
+		switch mode {
+		case "":
+			log.Printf("stale method :: %s is not found in the openapi doc (filename=%s, use --prune)", name, f.path)
+		case PruneModeDeprecate:
+			if strings.Contains(decl.Doc.Text(), deprecatedMarker) {
+				continue
+			}
+			comments := strings.Split(strings.TrimSpace(decl.Doc.Text()), "\n")
+			comments = append(comments, "", deprecatedMarker)
+			replaceDoc(f, decl, comments)
+			modified = true
+			log.Printf("prune method :: mode=%s %s (filename=%s)", mode, name, f.path)
+		case PruneModeDelete:
+			deleteDecl(f, decl)
+			delete(f.methods, name)
+			modified = true
+			log.Printf("prune method :: mode=%s %s (filename=%s)", mode, name, f.path)
+		default:
+			panic(fmt.Sprintf("unexpected prune mode: %q", mode))
+		}
+	}
+	return modified
+}
+
+// replaceDoc replaces the doc comment of decl.
+func replaceDoc(f *file, decl *ast.FuncDecl, comments []string) {
+	prevComment := decl.Doc
+	clines := make([]*ast.Comment, len(comments))
+	pos := decl.Pos() - 1 // hack
+	for i, line := range comments {
+		if line == "" {
+			clines[i] = &ast.Comment{Slash: pos, Text: "//"}
+			continue
+		}
+		clines[i] = &ast.Comment{Slash: pos, Text: "// " + line}
+	}
+	decl.Doc = &ast.CommentGroup{List: clines}
+	for i, cg := range f.syntax.Comments {
+		if cg == prevComment {
+			f.syntax.Comments[i] = decl.Doc
+			return
+		}
+	}
+	f.syntax.Comments = append(f.syntax.Comments, decl.Doc)
+}
+
+// deleteDecl removes decl and its comments (doc comment and comments in the body) from the file.
+func deleteDecl(f *file, decl *ast.FuncDecl) {
+	decls := f.syntax.Decls[:0]
+	for _, d := range f.syntax.Decls {
+		if d != decl {
+			decls = append(decls, d)
+		}
+	}
+	f.syntax.Decls = decls
+
+	start, end := decl.Pos(), decl.End()
+	if decl.Doc != nil {
+		start = decl.Doc.Pos()
+	}
+	comments := f.syntax.Comments[:0]
+	for _, cg := range f.syntax.Comments {
+		if start <= cg.Pos() && cg.End() <= end {
+			continue
+		}
+		comments = append(comments, cg)
+	}
+	f.syntax.Comments = comments
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/")
+
+// setupFixture copies testdata/<name> into the temporary directory, and changes the current directory to it.
+// The fixture has openapi.json, oapigen/ (--src) and api/ (--dst).
+func setupFixture(t *testing.T, name string) Options {
+	t.Helper()
+	src, err := filepath.Abs(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	dir := t.TempDir()
+	if err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0755)
+		}
+		if strings.HasSuffix(rel, ".golden") {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, rel), b, 0644)
+	}); err != nil {
+		t.Fatalf("copy fixture: %+v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	args := os.Args
+	os.Args = []string{"gen-stub"} // for the "Generated by" header
+	t.Cleanup(func() {
+		os.Args = args
+		os.Chdir(cwd) // nolint
+	})
+
+	target, err := LookupTarget("strict")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	return Options{
+		SrcDir:     "oapigen",
+		DstDirList: []string{"api"},
+		DocName:    "openapi.json",
+		Target:     target,
+		GuessImportPath: func(dir string) string {
+			return "example.com/fixture/" + filepath.ToSlash(dir)
+		},
+	}
+}
+
+// assertGolden compares the file with the golden file (the path is relative to the package directory).
+func assertGolden(t *testing.T, pkgdir string, filename string, golden string) {
+	t.Helper()
+	got, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("read generated file: %+v", err)
+	}
+	golden = filepath.Join(pkgdir, golden)
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatalf("update golden file: %+v", err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update): %+v", err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("%s mismatch (-want +got):\n%s", filename, diff)
+	}
+}
+
+func TestStaleMethods(t *testing.T) {
+	fset := token.NewFileSet()
+	syntax, err := parser.ParseFile(fset, "testdata/prune/api/greeting.go", nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("unexpected error (parse): %+v", err)
+	}
+	f := &file{path: "greeting.go", syntax: syntax, methods: map[string]*ast.FuncDecl{}}
+	for _, decl := range syntax.Decls {
+		if decl, ok := decl.(*ast.FuncDecl); ok && decl.Recv != nil {
+			f.methods[decl.Name.Name] = decl
+		}
+	}
+	defs := []*def{{field: &ast.Field{Names: []*ast.Ident{ast.NewIdent("Hello")}}}}
+
+	var got []string
+	for _, decl := range StaleMethods(f, defs) {
+		got = append(got, decl.Name.Name)
+	}
+	want := []string{"Bye"} // Hello is in the openapi doc, Greet is hand-written
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("StaleMethods() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	pkgdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mode   string
+		golden string
+	}{
+		{name: "warn", mode: "", golden: "testdata/prune/greeting-warn.go.golden"}, // not modified
+		{name: "deprecate", mode: PruneModeDeprecate, golden: "testdata/prune/greeting-deprecate.go.golden"},
+		{name: "delete", mode: PruneModeDelete, golden: "testdata/prune/greeting-delete.go.golden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := setupFixture(t, "prune")
+			options.Prune = tt.mode
+			if err := run(options); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			assertGolden(t, pkgdir, "api/greeting.go", tt.golden)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("stale", func(t *testing.T) {
+		options := setupFixture(t, "prune")
+		options.Check = true
+		if err := run(options); !errors.Is(err, ErrOutOfSync) {
+			t.Fatalf("want ErrOutOfSync, but got %v", err)
+		}
+		if _, err := os.Stat("api/controller.go"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("no files should be written with --check, but api/controller.go is found (err=%v)", err)
+		}
+	})
+
+	t.Run("in-sync", func(t *testing.T) {
+		options := setupFixture(t, "prune")
+		options.Prune = PruneModeDelete
+		if err := run(options); err != nil {
+			t.Fatalf("unexpected error (generate): %+v", err)
+		}
+		options.Prune = ""
+		options.Check = true
+		if err := run(options); err != nil {
+			t.Errorf("want nil after the generation, but got %+v", err)
+		}
+	})
+}
+
+// TestCheckExitStatus runs main() in the subprocess, and checks the exit status with --check.
+func TestCheckExitStatus(t *testing.T) {
+	if args := os.Getenv("GEN_STUB_TEST_ARGS"); args != "" {
+		os.Args = append([]string{"gen-stub"}, strings.Fields(args)...)
+		main()
+		return
+	}
+
+	fixture, err := filepath.Abs("testdata/prune")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	args := strings.Join([]string{
+		"--src=" + filepath.Join(fixture, "oapigen"),
+		"--dst=" + filepath.Join(fixture, "api"),
+		"--doc=" + filepath.Join(fixture, "openapi.json"),
+		"--check",
+	}, " ")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCheckExitStatus$")
+	cmd.Env = append(os.Environ(), "GEN_STUB_TEST_ARGS="+args)
+	out, err := cmd.CombinedOutput()
+
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		t.Fatalf("want the exit error, but got %v\n%s", err, out)
+	}
+	if want, got := 1, ee.ExitCode(); want != got {
+		t.Errorf("exit status: want=%d, but got=%d\n%s", want, got, out)
+	}
+	if want := ErrOutOfSync.Error(); !strings.Contains(string(out), want) {
+		t.Errorf("want %q in the output, but not found\n%s", want, out)
+	}
+}
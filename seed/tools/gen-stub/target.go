@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Target is the kind of server interface generated by oapi-codegen.
+type Target struct {
+	Name          string
+	InterfaceName string // the interface scanned in --src
+	Strict        bool   // the response types (e.g. Suggest200JSONResponse) are generated
+
+	// for doc comments
+	bodyHint   func(name string, bodyType string) string
+	paramsHint string // the variable holding query/header/cookie parameters
+
+	// for --test
+	handlerImports  []string
+	handlerTemplate string
+}
+
+var targets = []*Target{
+	{
+		Name:          "strict", // strict-server + chi-server
+		InterfaceName: "StrictServerInterface",
+		Strict:        true,
+		bodyHint: func(name string, bodyType string) string {
+			return "request.Body " + bodyType
+		},
+		paramsHint:     "request.Params",
+		handlerImports: []string{"net/http", "os", "strconv", "github.com/go-chi/chi/v5", "github.com/go-chi/chi/v5/middleware"},
+		handlerTemplate: `
+func newHandler(ssi oapigen.StrictServerInterface) http.Handler {
+	router := chi.NewRouter()
+	if ok, _ := strconv.ParseBool(os.Getenv("DEBUG")); ok {
+		router.Use(middleware.Logger)
+	}
+	return oapigen.HandlerFromMux(oapigen.NewStrictHandler(ssi, nil), router)
+}
+`,
+	},
+	{
+		Name:          "chi", // chi-server
+		InterfaceName: "ServerInterface",
+		bodyHint: func(name string, bodyType string) string {
+			return fmt.Sprintf("need: var body %s; json.NewDecoder(r.Body).Decode(&body); ", strings.TrimPrefix(bodyType, "*"))
+		},
+		paramsHint:     "params",
+		handlerImports: []string{"net/http", "os", "strconv", "github.com/go-chi/chi/v5", "github.com/go-chi/chi/v5/middleware"},
+		handlerTemplate: `
+func newHandler(si oapigen.ServerInterface) http.Handler {
+	router := chi.NewRouter()
+	if ok, _ := strconv.ParseBool(os.Getenv("DEBUG")); ok {
+		router.Use(middleware.Logger)
+	}
+	return oapigen.HandlerFromMux(si, router)
+}
+`,
+	},
+	{
+		Name:          "echo", // echo-server
+		InterfaceName: "ServerInterface",
+		bodyHint: func(name string, bodyType string) string {
+			return fmt.Sprintf("need: var body %s; ctx.Bind(&body); ", strings.TrimPrefix(bodyType, "*"))
+		},
+		paramsHint:     "params",
+		handlerImports: []string{"net/http", "os", "strconv", "github.com/labstack/echo/v4", "github.com/labstack/echo/v4/middleware"},
+		handlerTemplate: `
+func newHandler(si oapigen.ServerInterface) http.Handler {
+	e := echo.New()
+	if ok, _ := strconv.ParseBool(os.Getenv("DEBUG")); ok {
+		e.Use(middleware.Logger())
+	}
+	oapigen.RegisterHandlers(e, si)
+	return e
+}
+`,
+	},
+	{
+		Name:          "gin", // gin-server
+		InterfaceName: "ServerInterface",
+		bodyHint: func(name string, bodyType string) string {
+			return fmt.Sprintf("need: var body %s; c.ShouldBindJSON(&body); ", strings.TrimPrefix(bodyType, "*"))
+		},
+		paramsHint:     "params",
+		handlerImports: []string{"net/http", "os", "strconv", "github.com/gin-gonic/gin"},
+		handlerTemplate: `
+func newHandler(si oapigen.ServerInterface) http.Handler {
+	router := gin.New()
+	if ok, _ := strconv.ParseBool(os.Getenv("DEBUG")); ok {
+		router.Use(gin.Logger())
+	}
+	oapigen.RegisterHandlers(router, si)
+	return router
+}
+`,
+	},
+}
+
+// LookupTarget returns the target by name (strict, chi, echo, gin).
+func LookupTarget(name string) (*Target, error) {
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		if t.Name == name {
+			return t, nil
+		}
+		names[i] = t.Name
+	}
+	return nil, fmt.Errorf("unexpected target: %q (%s)", name, strings.Join(names, "|"))
+}
+
+func (t *Target) String() string {
+	return t.Name
+}
+
+var rxMajorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// PackageName guesses the package name from the import path (e.g. github.com/labstack/echo/v4 -> echo).
+func PackageName(importPath string) string {
+	name := path.Base(importPath)
+	if rxMajorVersion.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// PackageNamesInUse returns the package names referenced in the node (e.g. context, oapigen).
+func PackageNamesInUse(node ast.Node) []string {
+	seen := map[string]bool{}
+	ast.Inspect(node, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				seen[x.Name] = true
+			}
+		}
+		return true
+	})
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReceiverName returns the receiver name of the method, which doesn't conflict with the parameter names (e.g. gin's c *gin.Context).
+func ReceiverName(fn *ast.FuncType) string {
+	used := map[string]bool{}
+	for _, fields := range []*ast.FieldList{fn.Params, fn.Results} {
+		if fields == nil {
+			continue
+		}
+		for _, p := range fields.List {
+			for _, name := range p.Names {
+				used[name.Name] = true
+			}
+		}
+	}
+	for _, name := range []string{"c", "ctrl", "controller"} {
+		if !used[name] {
+			return name
+		}
+	}
+	return "_"
+}
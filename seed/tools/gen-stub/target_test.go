@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/deepmap/oapi-codegen/pkg/codegen"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestLookupTarget(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantInterface string
+		wantErr       bool
+	}{
+		{name: "strict", wantInterface: "StrictServerInterface"},
+		{name: "chi", wantInterface: "ServerInterface"},
+		{name: "echo", wantInterface: "ServerInterface"},
+		{name: "gin", wantInterface: "ServerInterface"},
+		{name: "gorilla", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupTarget(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if want, got := tt.wantInterface, got.InterfaceName; want != got {
+				t.Errorf("InterfaceName: want=%q, but got=%q", want, got)
+			}
+		})
+	}
+}
+
+// TestTargetBuild generates oapigen (by oapi-codegen) and the stubs with the tests (by gen-stub --test) for each target
+// into the temporary module, and checks that they can be compiled.
+func TestTargetBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go build is slow, skipped with -short")
+	}
+	gomod, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		t.Fatalf("go env GOMOD: %+v", err)
+	}
+	rootdir := filepath.Dir(strings.TrimSpace(string(gomod)))
+
+	tests := []struct {
+		target   string
+		generate codegen.GenerateOptions // the same as seed/tools/oapi-conf.yaml, for --target=strict
+	}{
+		{target: "strict", generate: codegen.GenerateOptions{Models: true, ChiServer: true, Strict: true}},
+		{target: "chi", generate: codegen.GenerateOptions{Models: true, ChiServer: true}},
+		{target: "echo", generate: codegen.GenerateOptions{Models: true, EchoServer: true}},
+		{target: "gin", generate: codegen.GenerateOptions{Models: true, GinServer: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			options := setupFixture(t, "testgen")
+			target, err := LookupTarget(tt.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			options.Target = target
+			options.Test = true
+
+			// the module having the same dependencies as this repository
+			b, err := os.ReadFile(filepath.Join(rootdir, "go.mod"))
+			if err != nil {
+				t.Fatalf("read go.mod: %+v", err)
+			}
+			b = regexp.MustCompile(`(?m)^module .+$`).ReplaceAll(b, []byte("module example.com/fixture"))
+			if err := os.WriteFile("go.mod", b, 0644); err != nil {
+				t.Fatalf("write go.mod: %+v", err)
+			}
+			if b, err = os.ReadFile(filepath.Join(rootdir, "go.sum")); err != nil {
+				t.Fatalf("read go.sum: %+v", err)
+			}
+			if err := os.WriteFile("go.sum", b, 0644); err != nil {
+				t.Fatalf("write go.sum: %+v", err)
+			}
+
+			doc, err := openapi3.NewLoader().LoadFromFile(options.DocName)
+			if err != nil {
+				t.Fatalf("load openapi doc: %+v", err)
+			}
+			code, err := codegen.Generate(doc, codegen.Configuration{PackageName: "oapigen", Generate: tt.generate})
+			if err != nil {
+				t.Fatalf("oapi-codegen: %+v", err)
+			}
+			if err := os.WriteFile("oapigen/server.go", []byte(code), 0644); err != nil {
+				t.Fatalf("write oapigen: %+v", err)
+			}
+
+			if err := run(options); err != nil {
+				t.Fatalf("gen-stub: %+v", err)
+			}
+			for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}} { // vet compiles the tests too
+				if out, err := exec.Command("go", args...).CombinedOutput(); err != nil {
+					t.Errorf("go %s: %+v\n%s", strings.Join(args, " "), err, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+
+	oapigen "example.com/fixture/oapigen"
+)
+
+type GreetingController struct{}
+
+func NewGreetingController() *GreetingController {
+	return &GreetingController{}
+}
+
+// Hello is endpoint of GET /hello
+// say hello
+//
+// * 200    :oapigen.Hello200Response            -- "ok"
+func (c *GreetingController) Hello(ctx context.Context, request oapigen.HelloRequestObject) (response oapigen.HelloResponseObject, err error) {
+	return
+}
+
+// Bye is endpoint of GET /bye
+// say bye
+//
+// * 200    :oapigen.Bye200Response              -- "ok"
+func (c *GreetingController) Bye(ctx context.Context) (err error) {
+	// the operation is removed from the openapi doc
+	return
+}
+
+// Greet is hand-written (not generated), then it is not stale.
+func (c *GreetingController) Greet(name string) string {
+	return "hello " + name
+}
@@ -0,0 +1,13 @@
+package oapigen
+
+import "context"
+
+type HelloRequestObject struct{}
+
+type HelloResponseObject interface{}
+
+type StrictServerInterface interface {
+
+	// (GET /hello)
+	Hello(ctx context.Context, request HelloRequestObject) (HelloResponseObject, error)
+}
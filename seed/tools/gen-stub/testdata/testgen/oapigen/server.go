@@ -0,0 +1,20 @@
+package oapigen
+
+import "context"
+
+type HelloRequestObject struct{}
+
+type HelloResponseObject interface{}
+
+type GreetRequestObject struct{}
+
+type GreetResponseObject interface{}
+
+type StrictServerInterface interface {
+
+	// (GET /hello)
+	Hello(ctx context.Context, request HelloRequestObject) (HelloResponseObject, error)
+
+	// (POST /hello)
+	Greet(ctx context.Context, request GreetRequestObject) (GreetResponseObject, error)
+}
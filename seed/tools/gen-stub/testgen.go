@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// testPackage is the scanned test files in a dst dir.
+type testPackage struct {
+	dir     string
+	pkgname string // e.g. api_test, or api if the existing tests are in the package itself
+	files   map[string]*ast.File
+	funcs   map[string]bool // top-level function names (e.g. TestEmojiSuggest, newHandler)
+}
+
+func scanTestPackage(fset *token.FileSet, dstdir string) (*testPackage, error) {
+	onlyTestFile := func(info fs.FileInfo) bool {
+		return strings.HasSuffix(info.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, dstdir, onlyTestFile, parser.AllErrors|parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse test files: %w", err)
+	}
+
+	// the package of the existing tests is used (the external test package is preferred, if both are found)
+	pkgname := PackageNameFromDir(dstdir) + "_test"
+	for name := range pkgs {
+		pkgname = name
+		if strings.HasSuffix(name, "_test") {
+			break
+		}
+	}
+	tp := &testPackage{dir: dstdir, pkgname: pkgname, files: map[string]*ast.File{}, funcs: map[string]bool{}}
+	for _, pkg := range pkgs {
+		for filename, f := range pkg.Files {
+			tp.files[filename] = f
+			for _, decl := range f.Decls {
+				if decl, ok := decl.(*ast.FuncDecl); ok && decl.Recv == nil {
+					tp.funcs[decl.Name.Name] = true
+				}
+			}
+		}
+	}
+	return tp, nil
+}
+
+// Internal reports whether the tests are in the package itself (not <pkg>_test).
+func (tp *testPackage) Internal() bool {
+	return !strings.HasSuffix(tp.pkgname, "_test")
+}
+
+// testGenerator generates the httptest based test skeletons, for each operation.
+type testGenerator struct {
+	fset     *token.FileSet
+	emitter  *emitter
+	packages map[string]*testPackage // dstdir -> test package
+
+	srcImportPath string // import path of oapigen
+	importPath    func(dir string) string
+	target        *Target
+}
+
+// EmitTagTests emits <tag>_test.go, only the tests not defined yet are added.
+//
+//	func TestEmojiSuggest(t *testing.T) { ... }
+func (g *testGenerator) EmitTagTests(dstdir string, normalizedTag string, typeName string, aggregateTypeName string, ctor *constructor, defs []*def) error {
+	tp, ok := g.packages[dstdir]
+	if !ok {
+		var err error
+		tp, err = scanTestPackage(g.fset, dstdir)
+		if err != nil {
+			return err
+		}
+		g.packages[dstdir] = tp
+	}
+	qualifier := PackageNameFromDir(dstdir) + "." // e.g. api.NewEmojiController
+	if tp.Internal() {
+		qualifier = ""
+	}
+	testPrefix := "Test" + strings.TrimSuffix(typeName, "Controller") // EmojiController -> TestEmoji
+	factoryName := "new" + typeName                                   // EmojiController -> newEmojiController
+
+	tests := new(bytes.Buffer)
+	imports := []string{}
+	for _, def := range defs {
+		name := def.field.Names[0].Name
+		testName := testPrefix + name // e.g. TestEmojiSuggest
+		if tp.funcs[testName] {
+			continue
+		}
+		tp.funcs[testName] = true
+		imports = append(imports, "net/http", "net/http/httptest", "testing")
+
+		body, err := exampleRequestBody(def.op)
+		if err != nil {
+			return fmt.Errorf("build example request body, method=%s: %w", name, err)
+		}
+		if body != "" {
+			imports = append(imports, "bytes")
+		}
+
+		newRequest := fmt.Sprintf("http.NewRequest(%q, tt.path, nil)", strings.ToUpper(def.method))
+		if body != "" {
+			newRequest = fmt.Sprintf("http.NewRequest(%q, tt.path, bytes.NewBufferString(tt.body))\n\t\t\treq.Header.Set(\"Content-Type\", \"application/json\")", strings.ToUpper(def.method))
+		}
+		fmt.Fprintf(tests, tagTestTemplate, testName, factoryName, examplePath(def), quote(body), newRequest) // nolint
+	}
+	if tests.Len() == 0 {
+		return nil
+	}
+
+	w := new(bytes.Buffer)
+	if !tp.funcs[factoryName] {
+		tp.funcs[factoryName] = true
+		imports = append(imports, g.srcImportPath)
+		if !tp.Internal() {
+			imports = append(imports, g.importPath(dstdir))
+		}
+		fmt.Fprintf(w, "\nfunc %s() oapigen.%s {\n", factoryName, g.target.InterfaceName) // nolint
+		if ctor != nil && len(ctor.params) > 0 {
+			fmt.Fprintf(w, "\tdeps := %sDependencies{} // TODO: set dependencies\n", qualifier) // nolint
+			fmt.Fprintf(w, "\treturn %sNew%s(deps)\n", qualifier, aggregateTypeName)            // nolint
+		} else {
+			fmt.Fprintf(w, "\treturn &%s%s{%s: %sNew%s()}\n", qualifier, aggregateTypeName, typeName, qualifier, typeName) // nolint
+		}
+		fmt.Fprintln(w, "}") // nolint
+	}
+	w.Write(tests.Bytes())
+	filename := filepath.Join(dstdir, strings.ReplaceAll(normalizedTag, "-", "_")+"_test.go")
+	return g.emit(tp, filename, imports, w.Bytes())
+}
+
+// EmitHandlerHelper emits newHandler() into controller_test.go, if it is not defined yet.
+func (g *testGenerator) EmitHandlerHelper(dstdir string) error {
+	tp, ok := g.packages[dstdir]
+	if !ok || tp.funcs["newHandler"] {
+		return nil
+	}
+	tp.funcs["newHandler"] = true
+
+	w := new(bytes.Buffer)
+	fmt.Fprint(w, g.target.handlerTemplate) // nolint
+
+	filename := filepath.Join(dstdir, "controller_test.go")
+	imports := append([]string{g.srcImportPath}, g.target.handlerImports...)
+	return g.emit(tp, filename, imports, w.Bytes())
+}
+
+func (g *testGenerator) emit(tp *testPackage, filename string, imports []string, code []byte) error {
+	f, ok := tp.files[filename]
+	if !ok {
+		syntax, err := parser.ParseFile(g.fset, filename, g.header(tp.pkgname, imports), parser.AllErrors|parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("create file %s: %w", filename, err)
+		}
+		f = syntax
+		tp.files[filename] = f
+	}
+	for _, path := range imports {
+		if path == g.srcImportPath {
+			astutil.AddNamedImport(g.fset, f, "oapigen", path)
+			continue
+		}
+		astutil.AddImport(g.fset, f, path)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := printer.Fprint(buf, g.fset, f); err != nil {
+		return fmt.Errorf("write %s: %w", filename, err)
+	}
+	buf.Write(code)
+
+	// the emitted code is parsed again, for the following emission into the same file
+	syntax, err := parser.ParseFile(g.fset, filename, buf.Bytes(), parser.AllErrors|parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return fmt.Errorf("parse generated %s: %w", filename, err)
+	}
+	tp.files[filename] = syntax
+	return g.emitter.Emit(filename, buf.Bytes())
+}
+
+const tagTestTemplate = `
+func %[1]s(t *testing.T) {
+	h := newHandler(%[2]s())
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "example", path: %[3]q, body: %[4]s, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := %[5]s
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%%d, but got=%%d", want, got)
+			}
+			// TODO: check response body
+		})
+	}
+}
+`
+
+// header returns the package clause and the import declaration (stdlib first, and others).
+func (g *testGenerator) header(pkgname string, imports []string) string {
+	var std, others []string
+	seen := map[string]bool{}
+	for _, path := range imports {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		switch {
+		case path == g.srcImportPath:
+			others = append(others, fmt.Sprintf("oapigen %q", path))
+		case strings.Contains(strings.Split(path, "/")[0], "."):
+			others = append(others, strconv.Quote(path))
+		default:
+			std = append(std, strconv.Quote(path))
+		}
+	}
+	sort.Strings(std)
+	sort.Slice(others, func(i, j int) bool {
+		return strings.Trim(others[i], `oapigen "`) < strings.Trim(others[j], `oapigen "`)
+	})
+
+	w := new(strings.Builder)
+	fmt.Fprintf(w, "package %s\n\nimport (\n", pkgname) // nolint
+	for _, x := range std {
+		fmt.Fprintf(w, "\t%s\n", x) // nolint
+	}
+	if len(std) > 0 && len(others) > 0 {
+		fmt.Fprintln(w, "") // nolint
+	}
+	for _, x := range others {
+		fmt.Fprintf(w, "\t%s\n", x) // nolint
+	}
+	fmt.Fprintln(w, ")") // nolint
+	return w.String()
+}
+
+// exampleRequestBody returns the JSON request body built from the example values of the schema.
+func exampleRequestBody(op *openapi3.Operation) (string, error) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return "", nil
+	}
+	content := op.RequestBody.Value.Content.Get("application/json")
+	if content == nil {
+		return "", nil
+	}
+	b, err := json.Marshal(exampleValue(content.Schema, 0))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// examplePath returns the request path, the path parameters and query parameters are filled by the example values.
+func examplePath(def *def) string {
+	path := def.path
+	q := url.Values{}
+	for _, p := range def.op.Parameters {
+		if p.Value == nil {
+			continue
+		}
+		v := exampleValue(p.Value.Schema, 0)
+		if p.Value.Example != nil {
+			v = p.Value.Example
+		}
+		switch p.Value.In {
+		case openapi3.ParameterInPath:
+			s := fmt.Sprintf("%v", v)
+			if s == "" || s == "0" {
+				s = "1"
+			}
+			path = strings.ReplaceAll(path, "{"+p.Value.Name+"}", url.PathEscape(s))
+		case openapi3.ParameterInQuery:
+			if p.Value.Required || p.Value.Example != nil {
+				q.Set(p.Value.Name, fmt.Sprintf("%v", v))
+			}
+		}
+	}
+	if len(q) > 0 {
+		path = path + "?" + q.Encode()
+	}
+	return path
+}
+
+// exampleValue returns the value of the schema, in the order of example, default, enum[0] and zero value.
+// For objects, only the required properties and the properties having example are included.
+func exampleValue(ref *openapi3.SchemaRef, depth int) any {
+	if ref == nil || ref.Value == nil || depth > 8 {
+		return nil
+	}
+	s := ref.Value
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case openapi3.TypeObject:
+		r := map[string]any{}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		required := map[string]bool{}
+		for _, name := range s.Required {
+			required[name] = true
+		}
+		for _, name := range names {
+			prop := s.Properties[name]
+			if required[name] || (prop.Value != nil && prop.Value.Example != nil) {
+				r[name] = exampleValue(prop, depth+1)
+			}
+		}
+		return r
+	case openapi3.TypeArray:
+		if v := exampleValue(s.Items, depth+1); v != nil {
+			return []any{v}
+		}
+		return []any{}
+	case openapi3.TypeString:
+		return ""
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		return 0
+	case openapi3.TypeBoolean:
+		return false
+	}
+	return nil
+}
+
+func quote(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
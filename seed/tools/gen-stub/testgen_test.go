@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEmitTests(t *testing.T) {
+	pkgdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	tests := []struct {
+		name     string
+		existing map[string]string // filename -> source, the test files existing before the generation
+		goldens  map[string]string // generated filename -> golden filename
+	}{
+		{
+			name: "external",
+			goldens: map[string]string{
+				"api/greeting_test.go":   "testdata/testgen/external-greeting_test.go.golden",
+				"api/controller_test.go": "testdata/testgen/external-controller_test.go.golden",
+			},
+		},
+		{
+			name: "internal",
+			existing: map[string]string{
+				"api/helper_test.go": "package api\n",
+			},
+			goldens: map[string]string{
+				"api/greeting_test.go":   "testdata/testgen/internal-greeting_test.go.golden",
+				"api/controller_test.go": "testdata/testgen/internal-controller_test.go.golden",
+			},
+		},
+		{
+			name: "both",
+			existing: map[string]string{
+				"api/helper_test.go":   "package api\n",
+				"api/greeting_test.go": "package api_test\n\nimport \"testing\"\n\nfunc TestGreetingHello(t *testing.T) {}\n",
+			},
+			goldens: map[string]string{
+				"api/greeting_test.go": "testdata/testgen/both-greeting_test.go.golden",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := setupFixture(t, "testgen")
+			options.Test = true
+			if err := os.MkdirAll("api", 0755); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			for filename, source := range tt.existing {
+				if err := os.WriteFile(filename, []byte(source), 0644); err != nil {
+					t.Fatalf("unexpected error: %+v", err)
+				}
+			}
+
+			if err := run(options); err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			for filename, golden := range tt.goldens {
+				assertGolden(t, pkgdir, filename, golden)
+			}
+		})
+	}
+}
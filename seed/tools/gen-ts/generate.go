@@ -0,0 +1,502 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Generate writes the typescript code, the interfaces for components/schemas and the fetch based client for each operation.
+// The path, query and header parameters, and the application/json request bodies are supported.
+// The response body is parsed as JSON, or returned as the text for the other media types (e.g. text/csv).
+// The operations having the 304 response return Conditional<T> (the status and the ETag), instead of throwing ApiError.
+//
+//	export interface EmojiDefinition { alias: string; char: string; }
+//	export class Client { async suggest(body: SuggestRequestBody, init?: RequestInit): Promise<SuggestResponse> { ... } }
+func Generate(w io.Writer, doc *openapi3.T, docName string) error {
+	g := &generator{w: w}
+	g.Printf("// Code generated by seed/tools/gen-ts from %s. DO NOT EDIT.\n", filepath.Base(docName))
+	g.Printf("/* eslint-disable */\n")
+
+	// components/schemas
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		ref := doc.Components.Schemas[name]
+		if ref.Value == nil {
+			continue
+		}
+		g.Printf("\n")
+		g.Comment("", ref.Value.Description, nil)
+		if ref.Value.Type == openapi3.TypeObject && len(ref.Value.Properties) > 0 {
+			g.Printf("export interface %s %s\n", name, g.TypeOf(ref, ""))
+		} else {
+			g.Printf("export type %s = %s;\n", name, g.TypeOf(ref, ""))
+		}
+	}
+
+	// operations
+	ops := operations(doc)
+	for _, op := range ops {
+		if op.body != nil {
+			g.Printf("\n")
+			g.Printf("export type %sRequestBody = %s;\n", op.typeName, g.TypeOf(op.body, ""))
+		}
+		if len(op.params) > 0 {
+			g.Printf("\n")
+			g.Printf("export interface %sParams {\n", op.typeName)
+			for _, p := range op.params {
+				var tags []string
+				if p.Schema != nil && p.Schema.Value != nil && p.Schema.Value.Default != nil {
+					defaultValue, _ := json.Marshal(p.Schema.Value.Default)
+					tags = append(tags, "@default "+string(defaultValue))
+				}
+				g.Comment("  ", p.Description, tags)
+				optional := "?"
+				if p.Required {
+					optional = ""
+				}
+				g.Printf("  %s%s: %s;\n", propertyName(p.Name), optional, g.TypeOf(p.Schema, "  "))
+			}
+			g.Printf("}\n")
+		}
+		g.Printf("\n")
+		responseType := g.TypeOf(op.response, "")
+		if op.text {
+			if op.response == nil {
+				responseType = "string"
+			} else {
+				responseType += " | string"
+			}
+		}
+		g.Printf("export type %sResponse = %s;\n", op.typeName, responseType)
+	}
+
+	g.Printf("%s", clientHeader)
+	for _, op := range ops {
+		g.Printf("\n")
+		g.Comment("  ", op.doc, nil)
+
+		var args []string
+		optional := "?" // params is optional, if all parameters are optional
+		if len(op.params) > 0 {
+			for _, p := range op.params {
+				if p.Required {
+					optional = ""
+				}
+			}
+			args = append(args, fmt.Sprintf("params%s: %sParams", optional, op.typeName))
+		}
+		if op.body != nil {
+			args = append(args, fmt.Sprintf("body: %sRequestBody", op.typeName))
+		}
+		args = append(args, "init?: RequestInit")
+		returnType, call := op.typeName+"Response", "request"
+		if op.conditional {
+			returnType, call = "Conditional<"+returnType+">", "conditional"
+		}
+		g.Printf("  async %s(%s): Promise<%s> {\n", op.funcName, strings.Join(args, ", "), returnType)
+
+		path := strings.ReplaceAll(op.path, "`", "\\`")
+		for _, p := range op.params {
+			if p.In == openapi3.ParameterInPath {
+				path = strings.ReplaceAll(path, "{"+p.Name+"}", fmt.Sprintf("${encodeURIComponent(String(params%s))}", propertyAccess(p.Name)))
+			}
+		}
+		var query, headers []string
+		for _, p := range op.params {
+			switch p.In {
+			case openapi3.ParameterInQuery:
+				query = append(query, fmt.Sprintf("%q: params%s", p.Name, optionalAccess(optional, p.Name)))
+			case openapi3.ParameterInHeader:
+				headers = append(headers, fmt.Sprintf("%q: params%s", p.Name, optionalAccess(optional, p.Name)))
+			}
+		}
+		options := []string{}
+		if len(query) > 0 {
+			options = append(options, fmt.Sprintf("query: { %s }", strings.Join(query, ", ")))
+		}
+		if len(headers) > 0 {
+			options = append(options, fmt.Sprintf("headers: { %s }", strings.Join(headers, ", ")))
+		}
+		if op.body != nil {
+			options = append(options, "body")
+		}
+		arg := "{}"
+		if len(options) > 0 {
+			arg = "{ " + strings.Join(options, ", ") + " }"
+		}
+		g.Printf("    return this.%s<%sResponse>(%q, `%s`, %s, init);\n", call, op.typeName, strings.ToUpper(op.method), path, arg)
+		g.Printf("  }\n")
+	}
+	g.Printf("%s", clientFooter)
+	return g.err
+}
+
+type generator struct {
+	w   io.Writer
+	err error
+}
+
+func (g *generator) Printf(format string, args ...any) {
+	if g.err != nil {
+		return
+	}
+	_, g.err = fmt.Fprintf(g.w, format, args...)
+}
+
+// Comment writes the JSDoc comment (if the doc or tags are not empty).
+func (g *generator) Comment(indent string, doc string, tags []string) {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, strings.ReplaceAll(line, "*/", "*\\/"))
+		}
+	}
+	lines = append(lines, tags...)
+	switch len(lines) {
+	case 0:
+	case 1:
+		g.Printf("%s/** %s */\n", indent, lines[0])
+	default:
+		g.Printf("%s/**\n", indent)
+		for _, line := range lines {
+			g.Printf("%s * %s\n", indent, line)
+		}
+		g.Printf("%s */\n", indent)
+	}
+}
+
+// TypeOf returns the typescript type of the schema.
+func (g *generator) TypeOf(ref *openapi3.SchemaRef, indent string) string {
+	if ref == nil {
+		return "void"
+	}
+	if ref.Ref != "" {
+		return refName(ref.Ref)
+	}
+	s := ref.Value
+	if s == nil {
+		return "unknown"
+	}
+
+	var typ string
+	switch {
+	case len(s.Enum) > 0:
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			b, _ := json.Marshal(v)
+			values[i] = string(b)
+		}
+		typ = strings.Join(values, " | ")
+	case len(s.AllOf) > 0:
+		typ = g.join(s.AllOf, " & ", indent)
+	case len(s.OneOf) > 0:
+		typ = g.join(s.OneOf, " | ", indent)
+	case len(s.AnyOf) > 0:
+		typ = g.join(s.AnyOf, " | ", indent)
+	default:
+		switch s.Type {
+		case openapi3.TypeString:
+			typ = "string"
+		case openapi3.TypeInteger, openapi3.TypeNumber:
+			typ = "number"
+		case openapi3.TypeBoolean:
+			typ = "boolean"
+		case openapi3.TypeArray:
+			typ = g.TypeOf(s.Items, indent)
+			if strings.ContainsAny(typ, "|&") {
+				typ = "(" + typ + ")"
+			}
+			typ += "[]"
+		case openapi3.TypeObject, "":
+			typ = g.objectType(s, indent)
+		default:
+			typ = "unknown"
+		}
+	}
+	if s.Nullable {
+		typ += " | null"
+	}
+	return typ
+}
+
+func (g *generator) objectType(s *openapi3.Schema, indent string) string {
+	if len(s.Properties) == 0 {
+		if s.AdditionalProperties.Schema != nil {
+			return fmt.Sprintf("Record<string, %s>", g.TypeOf(s.AdditionalProperties.Schema, indent))
+		}
+		if s.Type == "" {
+			return "unknown"
+		}
+		return "Record<string, unknown>"
+	}
+
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	b := new(strings.Builder)
+	inner := &generator{w: b}
+	inner.Printf("{\n")
+	for _, name := range sortedProperties(s) {
+		prop := s.Properties[name]
+		var tags []string
+		if prop.Value != nil && prop.Value.Example != nil {
+			example, _ := json.Marshal(prop.Value.Example)
+			tags = append(tags, "@example "+string(example))
+		}
+		if prop.Value != nil && prop.Value.Default != nil {
+			defaultValue, _ := json.Marshal(prop.Value.Default)
+			tags = append(tags, "@default "+string(defaultValue))
+		}
+		doc := ""
+		if prop.Value != nil {
+			doc = prop.Value.Description
+		}
+		inner.Comment(indent+"  ", doc, tags)
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		inner.Printf("%s  %s%s: %s;\n", indent, propertyName(name), optional, g.TypeOf(prop, indent+"  "))
+	}
+	inner.Printf("%s}", indent)
+	return b.String()
+}
+
+func (g *generator) join(refs openapi3.SchemaRefs, sep string, indent string) string {
+	types := make([]string, len(refs))
+	for i, ref := range refs {
+		types[i] = g.TypeOf(ref, indent)
+	}
+	return strings.Join(types, sep)
+}
+
+// sortedProperties returns the property names, the required ones first (in the order of required), and the others in alphabetical order.
+// (the order of properties in the doc is lost by kin-openapi)
+func sortedProperties(s *openapi3.Schema) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type operation struct {
+	method   string
+	path     string
+	funcName string // e.g. suggest
+	typeName string // e.g. Suggest
+	doc      string
+
+	params      []*openapi3.Parameter
+	body        *openapi3.SchemaRef
+	response    *openapi3.SchemaRef // the schema of 2xx application/json response (nil if no content)
+	text        bool                // the 2xx response has the media types other than JSON (returned as the text)
+	conditional bool                // the 304 response is declared (Conditional<T>)
+}
+
+// operations returns the operations sorted by path and method.
+func operations(doc *openapi3.T) []*operation {
+	var r []*operation
+	for _, path := range sortedKeys(doc.Paths) {
+		item := doc.Paths[path]
+		for _, method := range sortedKeys(item.Operations()) {
+			op := item.Operations()[method]
+			name := op.OperationID
+			if name == "" {
+				name = strings.ToLower(method) + " " + path
+			}
+			x := &operation{
+				method:   method,
+				path:     path,
+				funcName: toCamelCase(name, false),
+				typeName: toCamelCase(name, true),
+				doc:      op.Summary,
+			}
+			if x.doc == "" {
+				x.doc = op.Description
+			}
+			for _, p := range append(append(openapi3.Parameters{}, item.Parameters...), op.Parameters...) {
+				if p.Value != nil && (p.Value.In == openapi3.ParameterInPath || p.Value.In == openapi3.ParameterInQuery || p.Value.In == openapi3.ParameterInHeader) {
+					x.params = append(x.params, p.Value)
+				}
+			}
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				if content := op.RequestBody.Value.Content.Get("application/json"); content != nil {
+					x.body = content.Schema
+				}
+			}
+			for _, code := range sortedKeys(op.Responses) {
+				res := op.Responses[code]
+				if !strings.HasPrefix(code, "2") || res.Value == nil {
+					continue
+				}
+				for mediaType, content := range res.Value.Content {
+					if mediaType == "application/json" {
+						x.response = content.Schema
+					} else {
+						x.text = true
+					}
+				}
+				break
+			}
+			_, x.conditional = op.Responses["304"]
+			r = append(r, x)
+		}
+	}
+	return r
+}
+
+var rxIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func propertyName(name string) string {
+	if rxIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+func propertyAccess(name string) string {
+	if rxIdentifier.MatchString(name) {
+		return "." + name
+	}
+	return fmt.Sprintf("[%q]", name)
+}
+
+// optionalAccess returns the property access of the params (e.g. ?.limit or ?.["If-None-Match"] if optional).
+func optionalAccess(optional string, name string) string {
+	access := propertyAccess(name)
+	if optional != "" && strings.HasPrefix(access, "[") {
+		return "?." + access
+	}
+	return optional + access
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// toCamelCase converts the operationId to the name (e.g. list_users -> listUsers or ListUsers).
+func toCamelCase(name string, upper bool) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !(('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9'))
+	})
+	for i, x := range parts {
+		if i == 0 && !upper {
+			parts[i] = strings.ToLower(x[:1]) + x[1:]
+			continue
+		}
+		parts[i] = strings.ToUpper(x[:1]) + x[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+const clientHeader = `
+export class ApiError extends globalThis.Error {
+  constructor(
+    readonly status: number,
+    readonly body: unknown,
+  ) {
+    super(` + "`emoji API error: status=${status}`" + `);
+  }
+}
+
+export interface ClientOptions {
+  baseUrl: string;
+  fetch?: typeof fetch;
+  headers?: Record<string, string>;
+}
+
+/** the result of the operation supporting If-None-Match, status is 304 if not modified (the cached body is still valid). */
+export type Conditional<T> = { status: 200; etag: string | null; body: T } | { status: 304; etag: string | null };
+
+type Query = Record<string, string | number | boolean | undefined | null>;
+type HeaderParams = Record<string, string | number | boolean | undefined | null>;
+
+export class Client {
+  constructor(private readonly options: ClientOptions) {}
+`
+
+const clientFooter = `
+  private async request<T>(method: string, path: string, options: RequestOptions, init?: RequestInit): Promise<T> {
+    const res = await this.send(method, path, options, init);
+    return (await decode(res)) as T;
+  }
+
+  private async conditional<T>(method: string, path: string, options: RequestOptions, init?: RequestInit): Promise<Conditional<T>> {
+    const res = await this.send(method, path, options, init);
+    const etag = res.headers.get("ETag");
+    if (res.status === 304) {
+      return { status: 304, etag };
+    }
+    return { status: 200, etag, body: (await decode(res)) as T };
+  }
+
+  private async send(method: string, path: string, options: RequestOptions, init?: RequestInit): Promise<Response> {
+    const url = new URL(this.options.baseUrl.replace(/\/$/, "") + path);
+    for (const [k, v] of Object.entries(options.query ?? {})) {
+      if (v !== undefined && v !== null) {
+        url.searchParams.set(k, String(v));
+      }
+    }
+    const headers: Record<string, string> = { Accept: "application/json", ...this.options.headers };
+    for (const [k, v] of Object.entries(options.headers ?? {})) {
+      if (v !== undefined && v !== null) {
+        headers[k] = String(v);
+      }
+    }
+    if (options.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    const res = await (this.options.fetch ?? fetch)(url.toString(), {
+      ...init,
+      method,
+      headers: { ...headers, ...(init?.headers as Record<string, string> | undefined) },
+      body: options.body === undefined ? undefined : JSON.stringify(options.body),
+    });
+    if (!res.ok && res.status !== 304) {
+      throw new ApiError(res.status, await decode(res));
+    }
+    return res;
+  }
+}
+
+type RequestOptions = { query?: Query; headers?: HeaderParams; body?: unknown };
+
+/** decode parses the JSON body, the body of the other media types (e.g. text/csv, application/x-ndjson) is returned as the text. */
+async function decode(res: Response): Promise<unknown> {
+  const text = await res.text();
+  if (text === "") {
+    return undefined;
+  }
+  const contentType = res.headers.get("Content-Type") ?? "";
+  if (contentType !== "" && !/^application\/([^;]+\+)?json\b/.test(contentType)) {
+    return text;
+  }
+  return JSON.parse(text);
+}
+`
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/")
+
+func TestGenerate(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	for _, filename := range files {
+		filename := filename
+		t.Run(filepath.Base(filename), func(t *testing.T) {
+			doc, err := openapi3.NewLoader().LoadFromFile(filename)
+			if err != nil {
+				t.Fatalf("load: %+v", err)
+			}
+
+			buf := new(bytes.Buffer)
+			if err := Generate(buf, doc, filename); err != nil {
+				t.Fatalf("generate: %+v", err)
+			}
+
+			golden := strings.TrimSuffix(filename, ".json") + ".ts"
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatalf("update golden file: %+v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden file (run with -update): %+v", err)
+			}
+			if diff := cmp.Diff(string(want), buf.String()); diff != "" {
+				t.Errorf("Generate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
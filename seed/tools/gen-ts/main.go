@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+)
+
+type Options struct {
+	DocName string // openapi doc
+	Output  string // output file (default: stdout)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("** ")
+
+	options := &Options{}
+	pflag.StringVar(&options.DocName, "doc", "", "target openapi doc (OAS3.0)")
+	pflag.StringVarP(&options.Output, "output", "o", "", "output file (default: stdout)")
+	pflag.Parse()
+
+	if options.DocName == "" {
+		pflag.Usage()
+		os.Exit(2)
+	}
+	if err := run(options); err != nil {
+		log.Fatalf("!! %+v", err)
+	}
+}
+
+func run(options *Options) error {
+	doc, err := openapi3.NewLoader().LoadFromFile(options.DocName)
+	if err != nil {
+		return fmt.Errorf("load %s: %w", options.DocName, err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := Generate(buf, doc, options.DocName); err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+	if options.Output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(options.Output, buf.Bytes(), 0644)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+)
+
+type Options struct {
+	DocName string
+	Addr    string
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("** ")
+
+	options := &Options{}
+	pflag.StringVar(&options.DocName, "doc", "openapi.json", "target openapi doc (OAS3.0)")
+	pflag.StringVar(&options.Addr, "addr", ":8080", "listen address")
+	pflag.Parse()
+
+	if err := run(options); err != nil {
+		log.Fatalf("!! %+v", err)
+	}
+}
+
+func run(options *Options) error {
+	doc, err := openapi3.NewLoader().LoadFromFile(options.DocName)
+	if err != nil {
+		return err
+	}
+	mock, err := NewMock(doc, log.New(os.Stderr, "", log.LstdFlags))
+	if err != nil {
+		return err
+	}
+	log.Printf("mock server (doc=%s) listening on %s", options.DocName, options.Addr)
+	return http.ListenAndServe(options.Addr, mock)
+}
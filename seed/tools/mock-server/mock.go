@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/podhmo/emoji-api/api/encoding"
+)
+
+// Mock serves every operation in the openapi doc, with the example values of the schemas.
+// The requests are validated by the doc (400 if invalid).
+//
+// The status code of the response can be chosen with the Prefer header (e.g. "Prefer: code=404"),
+// by default the first 2xx response is used. The media type is negotiated by the Accept header (see Render),
+// 501 if the example cannot be rendered in the media type.
+type Mock struct {
+	router routers.Router
+	logger *log.Logger
+}
+
+func NewMock(doc *openapi3.T, logger *log.Logger) (*Mock, error) {
+	// the servers are ignored, for matching the requests by path only (the mock server can be run on any host and port)
+	servers := doc.Servers
+	doc.Servers = nil
+	defer func() { doc.Servers = servers }()
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("new router: %w", err)
+	}
+	return &Mock{router: router, logger: logger}, nil
+}
+
+func (m *Mock) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	route, pathParams, err := m.router.FindRoute(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, routers.ErrMethodNotAllowed):
+			m.writeError(w, req, http.StatusMethodNotAllowed, err)
+		default:
+			m.writeError(w, req, http.StatusNotFound, err)
+		}
+		return
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+	}
+	if err := openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+		m.writeError(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	code, res, err := pickResponse(route.Operation, req.Header.Get("Prefer"))
+	if err != nil {
+		m.writeError(w, req, http.StatusNotImplemented, err)
+		return
+	}
+	status := http.StatusInternalServerError // default
+	if v, err := strconv.Atoi(code); err == nil {
+		status = v
+	}
+
+	mediaType, body, err := Render(res.Content, req.Header.Get("Accept"))
+	if err != nil {
+		m.writeError(w, req, http.StatusNotImplemented, fmt.Errorf("%w (operationId=%s)", err, route.Operation.OperationID))
+		return
+	}
+	m.logger.Printf("%s %s -> %d %s (operationId=%s)", req.Method, req.URL.Path, status, mediaType, route.Operation.OperationID)
+
+	if mediaType == "" {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", encoding.ContentType(mediaType))
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		m.logger.Printf("write response: %+v", err)
+	}
+}
+
+// Render returns the example of the response content, in the media type selected by the Accept header.
+// The first declared media type (application/json if declared) is used if nothing is acceptable, as the API server does.
+// The media type is "" if the response has no content.
+//
+//   - application/json (or +json): the example value
+//   - application/x-ndjson: the items of the JSON example, one per line
+//   - text/csv: the header (the property names) and the rows of the JSON example
+//   - the others: the string example (e.g. text/plain), error if not declared for the media types other than text/*
+func Render(content openapi3.Content, accept string) (string, []byte, error) {
+	if len(content) == 0 {
+		return "", nil, nil
+	}
+	mediaTypes := sortedKeys(content)
+	sort.SliceStable(mediaTypes, func(i, j int) bool { return mediaTypes[i] == encoding.JSON }) // JSON first
+	mediaType := encoding.Select(accept, mediaTypes)
+	if mediaType == "" {
+		mediaType = mediaTypes[0]
+	}
+
+	example := ExampleOf(content[mediaType])
+	buf := new(bytes.Buffer)
+	switch {
+	case mediaType == encoding.JSON || strings.HasSuffix(mediaType, "+json"):
+		if err := json.NewEncoder(buf).Encode(example); err != nil {
+			return "", nil, err
+		}
+		return mediaType, buf.Bytes(), nil
+	case example != "" && example != nil:
+		// the example declared for the media type itself
+	case mediaType == encoding.NDJSON:
+		items, ok := jsonExample(content).([]any)
+		if !ok {
+			return "", nil, fmt.Errorf("%s is not renderable: the JSON example is not an array", mediaType)
+		}
+		enc := json.NewEncoder(buf)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return "", nil, err
+			}
+		}
+		return mediaType, buf.Bytes(), nil
+	case mediaType == encoding.CSV:
+		items, ok := jsonExample(content).([]any)
+		if !ok {
+			return "", nil, fmt.Errorf("%s is not renderable: the JSON example is not an array", mediaType)
+		}
+		if err := writeCSV(buf, items); err != nil {
+			return "", nil, fmt.Errorf("%s is not renderable: %w", mediaType, err)
+		}
+		return mediaType, buf.Bytes(), nil
+	}
+
+	s, ok := example.(string)
+	if !ok || (s == "" && !strings.HasPrefix(mediaType, "text/")) { // e.g. image/png without the example
+		return "", nil, fmt.Errorf("%s is not renderable: no string example", mediaType)
+	}
+	return mediaType, []byte(s), nil
+}
+
+// jsonExample returns the example of application/json, for building NDJSON and CSV (nil if not declared).
+func jsonExample(content openapi3.Content) any {
+	if c := content.Get(encoding.JSON); c != nil {
+		return ExampleOf(c)
+	}
+	return nil
+}
+
+// writeCSV writes the items (the objects) as CSV, the header is the property names (sorted).
+func writeCSV(w io.Writer, items []any) error {
+	var header []string
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("the item is not an object: %T", item)
+		}
+		if header == nil {
+			header = sortedKeys(obj)
+		}
+		row := make([]string, len(header))
+		for i, k := range header {
+			if v := obj[k]; v != nil {
+				row[i] = fmt.Sprint(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	cw := csv.NewWriter(w)
+	if header != nil {
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// writeError writes the error response, in the same shape as design.Error ({"message": "..."}).
+func (m *Mock) writeError(w http.ResponseWriter, req *http.Request, status int, err error) {
+	m.logger.Printf("%s %s -> %d (%v)", req.Method, req.URL.Path, status, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": err.Error()}) // nolint
+}
+
+// pickResponse returns the response, chosen by the Prefer header (e.g. "code=404") or the first 2xx response.
+func pickResponse(op *openapi3.Operation, prefer string) (string, *openapi3.Response, error) {
+	for _, x := range strings.Split(prefer, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(x), "=")
+		if !ok || k != "code" {
+			continue
+		}
+		ref, ok := op.Responses[v]
+		if !ok || ref.Value == nil {
+			return "", nil, fmt.Errorf("response %s is not defined (operationId=%s)", v, op.OperationID)
+		}
+		return v, ref.Value, nil
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") && op.Responses[code].Value != nil {
+			return code, op.Responses[code].Value, nil
+		}
+	}
+	return "", nil, fmt.Errorf("2xx response is not defined (operationId=%s)", op.OperationID)
+}
+
+// ExampleOf returns the example value of the media type, in the order of example, examples and the value built from the schema.
+func ExampleOf(content *openapi3.MediaType) any {
+	if content.Example != nil {
+		return content.Example
+	}
+	for _, name := range sortedKeys(content.Examples) {
+		if ex := content.Examples[name]; ex.Value != nil && ex.Value.Value != nil {
+			return ex.Value.Value
+		}
+	}
+	return exampleValue(content.Schema, 0)
+}
+
+// exampleValue returns the value of the schema, in the order of example, default, enum[0] and zero value.
+// For objects, all properties are included.
+func exampleValue(ref *openapi3.SchemaRef, depth int) any {
+	if ref == nil || ref.Value == nil || depth > 8 {
+		return nil
+	}
+	s := ref.Value
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+
+	switch {
+	case len(s.AllOf) > 0:
+		r := map[string]any{}
+		for _, x := range s.AllOf {
+			if v, ok := exampleValue(x, depth+1).(map[string]any); ok {
+				for k, v := range v {
+					r[k] = v
+				}
+			}
+		}
+		return r
+	case len(s.OneOf) > 0:
+		return exampleValue(s.OneOf[0], depth+1)
+	case len(s.AnyOf) > 0:
+		return exampleValue(s.AnyOf[0], depth+1)
+	}
+
+	switch s.Type {
+	case openapi3.TypeObject:
+		r := map[string]any{}
+		for name, prop := range s.Properties {
+			r[name] = exampleValue(prop, depth+1)
+		}
+		return r
+	case openapi3.TypeArray:
+		if v := exampleValue(s.Items, depth+1); v != nil {
+			return []any{v}
+		}
+		return []any{}
+	case openapi3.TypeString:
+		return ""
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		return 0
+	case openapi3.TypeBoolean:
+		return false
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMock(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../../openapi.json")
+	if err != nil {
+		t.Fatalf("load: %+v", err)
+	}
+	mock, err := NewMock(doc, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("new mock: %+v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		prefer     string
+		wantStatus int
+		want       any
+	}{
+		{name: "suggest", method: "POST", path: "/emoji/suggest", body: `{"prefix": ":dizzy", "sort": "asc"}`,
+			wantStatus: http.StatusOK, want: []any{map[string]any{"alias": ":dizzy:", "char": "💫"}}},
+		{name: "translate", method: "POST", path: "/emoji/translate", body: `{"text": "hello :dizzy:"}`,
+			wantStatus: http.StatusOK, want: ""},
+		{name: "invalid-enum", method: "POST", path: "/emoji/suggest", body: `{"prefix": ":dizzy", "sort": "random"}`,
+			wantStatus: http.StatusBadRequest},
+		{name: "missing-required", method: "POST", path: "/emoji/translate", body: `{}`,
+			wantStatus: http.StatusBadRequest},
+		{name: "not-found", method: "POST", path: "/emoji/unknown", body: `{}`,
+			wantStatus: http.StatusNotFound},
+		{name: "method-not-allowed", method: "GET", path: "/emoji/suggest",
+			wantStatus: http.StatusMethodNotAllowed},
+		{name: "prefer-default", method: "POST", path: "/emoji/translate", body: `{"text": "hello"}`, prefer: "code=default",
+			wantStatus: http.StatusInternalServerError, want: map[string]any{"message": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.prefer != "" {
+				req.Header.Set("Prefer", tt.prefer)
+			}
+			rec := httptest.NewRecorder()
+			mock.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := tt.wantStatus, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			var got any
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %+v", err)
+			}
+			if tt.want == nil { // error response
+				if _, ok := got.(map[string]any)["message"]; !ok {
+					t.Errorf("want error response, but got %v", got)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("response body, mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMockExport(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("../../../openapi.json")
+	if err != nil {
+		t.Fatalf("load: %+v", err)
+	}
+	mock, err := NewMock(doc, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("new mock: %+v", err)
+	}
+
+	tests := []struct {
+		name            string
+		accept          string
+		wantContentType string
+		want            string
+	}{
+		{name: "default", accept: "", wantContentType: "application/json", want: `[{"alias":":dizzy:","char":"💫"}]` + "\n"},
+		{name: "ndjson", accept: "application/x-ndjson", wantContentType: "application/x-ndjson", want: `{"alias":":dizzy:","char":"💫"}` + "\n"},
+		{name: "csv", accept: "text/csv", wantContentType: "text/csv; charset=utf-8", want: "alias,char\n:dizzy:,💫\n"},
+		{name: "quality", accept: "application/json;q=0.5, text/*", wantContentType: "text/csv; charset=utf-8", want: "alias,char\n:dizzy:,💫\n"},
+		{name: "not-acceptable", accept: "text/html", wantContentType: "application/json", want: `[{"alias":":dizzy:","char":"💫"}]` + "\n"}, // as the API server
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/emoji/export", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			mock.ServeHTTP(rec, req)
+			res := rec.Result()
+
+			if want, got := http.StatusOK, res.StatusCode; want != got {
+				t.Fatalf("status code: want=%d, but got=%d", want, got)
+			}
+			if want, got := tt.wantContentType, res.Header.Get("Content-Type"); want != got {
+				t.Errorf("content-type: want=%q, but got=%q", want, got)
+			}
+			if diff := cmp.Diff(tt.want, rec.Body.String()); diff != "" {
+				t.Errorf("response body, mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMockNotRenderable(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "fixture", "version": "0.0.0"},
+  "paths": {
+    "/image": {
+      "get": {
+        "operationId": "image",
+        "responses": {"200": {"description": "ok", "content": {"image/png": {"schema": {"type": "string", "format": "binary"}}, "text/csv": {"schema": {"type": "string"}}}}}
+      }
+    }
+  }
+}`))
+	if err != nil {
+		t.Fatalf("load: %+v", err)
+	}
+	mock, err := NewMock(doc, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("new mock: %+v", err)
+	}
+
+	for _, accept := range []string{"image/png", "text/csv"} { // the binary example, and CSV without the JSON example
+		req := httptest.NewRequest("GET", "/image", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		mock.ServeHTTP(rec, req)
+
+		if want, got := http.StatusNotImplemented, rec.Code; want != got {
+			t.Errorf("status code of %s: want=%d, but got=%d (%s)", accept, want, got, rec.Body.String())
+		}
+	}
+}